@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var (
+	batchMsg    string
+	batchAuthor string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <id>",
+	Short: "Apply a series of operations from stdin as a single commit",
+	Long: `Read JSON-lines operations from stdin and apply them to a thread in one
+thread.Transaction, so a whole script's worth of edits lands as a single
+reviewable commit instead of one per operation. Each line is an object with
+an "op" field and, depending on the op, "text", "hash", and/or "new_text":
+
+  {"op": "add_note", "text": "..."}
+  {"op": "remove_note", "hash": "..."}
+  {"op": "edit_note", "hash": "...", "new_text": "..."}
+  {"op": "add_todo", "text": "..."}
+  {"op": "remove_todo", "hash": "..."}
+  {"op": "edit_todo", "hash": "...", "new_text": "..."}
+  {"op": "check_todo", "hash": "..."}
+  {"op": "uncheck_todo", "hash": "..."}
+  {"op": "append_log", "text": "..."}
+
+Blank lines are skipped. Any op failing (e.g. an unknown hash) aborts the
+whole batch; nothing is written or committed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringVarP(&batchMsg, "m", "m", "", "Commit message (default: synthesized from the applied operations)")
+	batchCmd.Flags().StringVar(&batchAuthor, "author", "", `Commit author as "Name <email>" (default: the repository's configured user)`)
+}
+
+// batchOp is one line of batch input.
+type batchOp struct {
+	Op      string `json:"op"`
+	Text    string `json:"text,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	NewText string `json:"new_text,omitempty"`
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+	ref := args[0]
+
+	file, err := workspace.FindByRef(ws, ref)
+	if err != nil {
+		return err
+	}
+
+	var idx *thread.Index
+	if paths, err := workspace.FindAllThreads(ws); err == nil {
+		idx = thread.BuildIndex(paths)
+	}
+
+	tx, err := thread.Begin(file, idx)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var op batchOp
+		if err := json.Unmarshal([]byte(line), &op); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if err := applyBatchOp(tx, op); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading batch operations: %w", err)
+	}
+
+	if err := tx.Commit(ws, git.CommitOptions{Message: batchMsg, Author: batchAuthor}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Applied batch to %s: %s\n", ref, tx.Summary())
+	return nil
+}
+
+// applyBatchOp dispatches a single batchOp to the matching Transaction
+// method.
+func applyBatchOp(tx *thread.Transaction, op batchOp) error {
+	switch op.Op {
+	case "add_note":
+		tx.AddNote(op.Text)
+	case "remove_note":
+		return tx.RemoveNote(op.Hash)
+	case "edit_note":
+		return tx.EditNote(op.Hash, op.NewText)
+	case "add_todo":
+		tx.AddTodo(op.Text)
+	case "remove_todo":
+		return tx.RemoveTodo(op.Hash)
+	case "edit_todo":
+		return tx.EditTodo(op.Hash, op.NewText)
+	case "check_todo":
+		return tx.CheckTodo(op.Hash)
+	case "uncheck_todo":
+		return tx.UncheckTodo(op.Hash)
+	case "append_log":
+		tx.AppendLog(op.Text)
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	return nil
+}
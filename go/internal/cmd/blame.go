@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"git.zib.de/cspiegel/threads/internal/output"
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var (
+	blameFormat string
+	blameJSON   bool
+)
+
+var blameCmd = &cobra.Command{
+	Use:               "blame <id>",
+	Short:             "Show who last touched each item in a thread",
+	Long:              `Show, for each frontmatter field, Note, Todo item, and Log entry, the author and date of the commit that last touched it.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeThreadIDs,
+	RunE:              runBlame,
+}
+
+func init() {
+	blameCmd.Flags().StringVarP(&blameFormat, "format", "f", "fancy", "Output format: fancy, plain, json, yaml")
+	blameCmd.Flags().BoolVar(&blameJSON, "json", false, "Output as JSON (shorthand for --format=json)")
+}
+
+func runBlame(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+	ref := args[0]
+
+	file, err := workspace.FindByRef(ws, ref)
+	if err != nil {
+		return err
+	}
+
+	var format output.Format
+	if blameJSON {
+		format = output.FormatJSON
+	} else {
+		format, _ = output.ParseFormat(blameFormat)
+		format = format.Resolve()
+	}
+
+	entries, err := thread.BlameItems(ws, file)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case output.FormatJSON:
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON serialization failed: %v", err)
+		}
+		fmt.Println(string(data))
+	case output.FormatYAML:
+		data, err := yaml.Marshal(entries)
+		if err != nil {
+			return fmt.Errorf("YAML serialization failed: %v", err)
+		}
+		fmt.Print(string(data))
+	default:
+		for _, e := range entries {
+			key := e.Key
+			if e.Status != "" {
+				key = fmt.Sprintf("%s (%s)", key, e.Status)
+			}
+			fmt.Printf("%-8s │ %-20s │ %-20s │ %-20s │ %s\n", e.Section, key, e.Author, e.Date, e.Text)
+		}
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -95,7 +96,7 @@ func runBody(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
@@ -10,13 +10,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
 
 var (
-	commitPending bool
-	commitMsg     string
-	commitAuto    bool
+	commitPending  bool
+	commitMsg      string
+	commitAuto     bool
+	commitWorktree bool
 )
 
 var commitCmd = &cobra.Command{
@@ -24,7 +26,10 @@ var commitCmd = &cobra.Command{
 	Short: "Commit thread changes",
 	Long: `Commit specific threads or all pending thread changes.
 
-Use --pending to commit all modified threads at once.`,
+Use --pending to commit all modified threads at once.
+Use --worktree to stage and commit inside an isolated git worktree, so
+unrelated in-progress edits in the working copy are never swept in and
+parallel 'threads' invocations don't race on the index.`,
 	RunE: runCommit,
 }
 
@@ -32,6 +37,7 @@ func init() {
 	commitCmd.Flags().BoolVar(&commitPending, "pending", false, "Commit all modified threads")
 	commitCmd.Flags().StringVarP(&commitMsg, "m", "m", "", "Commit message")
 	commitCmd.Flags().BoolVar(&commitAuto, "auto", false, "Auto-accept generated message")
+	commitCmd.Flags().BoolVar(&commitWorktree, "worktree", false, "Stage and commit inside an isolated git worktree")
 }
 
 func runCommit(cmd *cobra.Command, args []string) error {
@@ -72,7 +78,7 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(files) == 0 {
-		fmt.Println("No threads to commit.")
+		fmt.Println(i18n.T("No threads to commit."))
 		return nil
 	}
 
@@ -101,7 +107,11 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		relPaths = append(relPaths, relPath)
 	}
 
-	if err := git.Commit(ws, relPaths, msg); err != nil {
+	if commitWorktree {
+		if err := commitViaWorktree(ws, relPaths, msg); err != nil {
+			return err
+		}
+	} else if err := git.Commit(ws, relPaths, msg); err != nil {
 		return err
 	}
 
@@ -113,6 +123,35 @@ func runCommit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// commitViaWorktree stages and commits relPaths inside a disposable git
+// worktree, then cherry-picks the resulting commit onto ws's current
+// branch. This keeps the user's own dirty working tree untouched and lets
+// concurrent 'threads' invocations commit without fighting over one index.
+func commitViaWorktree(ws string, relPaths []string, msg string) error {
+	wt, err := git.NewWorktree(ws)
+	if err != nil {
+		return err
+	}
+	defer wt.Close()
+
+	for _, relPath := range relPaths {
+		if err := wt.CopyIn(relPath); err != nil {
+			return fmt.Errorf("copying %s into worktree: %w", relPath, err)
+		}
+	}
+
+	if err := git.Commit(wt.WorktreePath(), relPaths, msg); err != nil {
+		return err
+	}
+
+	hash, err := git.HeadCommit(wt.WorktreePath())
+	if err != nil {
+		return fmt.Errorf("resolving worktree commit: %w", err)
+	}
+
+	return git.MergeBack(ws, hash)
+}
+
 func isTerminal() bool {
 	stat, _ := os.Stdin.Stat()
 	return (stat.Mode() & os.ModeCharDevice) != 0
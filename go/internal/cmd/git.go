@@ -48,3 +48,91 @@ func runGit(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var (
+	gitCommitMessage string
+	gitCommitPush    bool
+	gitCommitSign    string
+	gitCommitDryRun  bool
+)
+
+var gitCommitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Commit pending thread changes via the embedded git backend",
+	RunE:  runGitCommit,
+}
+
+func init() {
+	gitCommitCmd.Flags().StringVarP(&gitCommitMessage, "m", "m", "", "Commit message (default: generated from the changed threads)")
+	gitCommitCmd.Flags().BoolVar(&gitCommitPush, "push", false, "Push after committing")
+	gitCommitCmd.Flags().StringVar(&gitCommitSign, "sign", "", "Path to an armored PGP private key to GPG-sign the commit with")
+	gitCommitCmd.Flags().BoolVar(&gitCommitDryRun, "dry-run", false, "Print what would be committed instead of committing")
+	gitCmd.AddCommand(gitCommitCmd)
+}
+
+// runGitCommit stages and commits every modified thread in one go, through
+// the go-git backend (no external git binary involved, signing included).
+// --dry-run prints a "Suggested:" block like runGit's (with the generated
+// or -m message, and a push only under --push) instead of acting, for
+// anyone who wants to review or run the equivalent commands themselves.
+func runGitCommit(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+
+	threads, err := workspace.FindAllThreads(ws)
+	if err != nil {
+		return err
+	}
+
+	var files, modified []string
+	for _, t := range threads {
+		relPath, _ := filepath.Rel(ws, t)
+		if git.HasChanges(ws, relPath) {
+			files = append(files, t)
+			modified = append(modified, relPath)
+		}
+	}
+
+	if len(modified) == 0 {
+		fmt.Println("No pending thread changes.")
+		return nil
+	}
+
+	msg := gitCommitMessage
+	if msg == "" {
+		msg = git.GenerateCommitMessage(ws, files)
+	}
+
+	if gitCommitDryRun {
+		fmt.Println("Pending thread changes:")
+		for _, f := range modified {
+			fmt.Printf("  %s\n", f)
+		}
+		fmt.Println()
+		fmt.Println("Suggested:")
+		suggested := fmt.Sprintf("  git add %s && git commit -m \"%s\"", strings.Join(modified, " "), msg)
+		if gitCommitPush {
+			suggested += " && git push"
+		}
+		fmt.Println(suggested)
+		return nil
+	}
+
+	repo, err := git.Open(ws)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.CommitWithOptions(modified, git.CommitOptions{Message: msg, SignKey: gitCommitSign}); err != nil {
+		return err
+	}
+	fmt.Printf("Committed %d thread file(s): %s\n", len(modified), msg)
+
+	if gitCommitPush {
+		if err := repo.Push(); err != nil {
+			return err
+		}
+		fmt.Println("Pushed.")
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/output"
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
+	"git.zib.de/cspiegel/threads/internal/thread/diff"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var (
+	historyFormat string
+	historyJSON   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:               "history <id>",
+	Short:             "Show a thread's history as high-level operations",
+	Long:              `Walk the commits touching a thread file and show what changed at each one - notes and todo items added, removed, edited, or checked off, and log entries appended - instead of a raw diff.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeThreadIDs,
+	RunE:              runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&historyFormat, "format", "f", "fancy", "Output format: fancy, plain, json, yaml")
+	historyCmd.Flags().BoolVar(&historyJSON, "json", false, "Output as JSON (shorthand for --format=json)")
+}
+
+// HistoryEntry is one commit's worth of changes to a thread file.
+type HistoryEntry struct {
+	Commit  string        `json:"commit" yaml:"commit"`
+	Author  string        `json:"author" yaml:"author"`
+	Date    string        `json:"date" yaml:"date"`
+	Message string        `json:"message" yaml:"message"`
+	Changes []diff.Change `json:"changes" yaml:"changes"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+	ref := args[0]
+
+	file, err := workspace.FindByRef(ws, ref)
+	if err != nil {
+		return err
+	}
+
+	relPath, err := filepath.Rel(ws, file)
+	if err != nil {
+		relPath = file
+	}
+
+	revs, err := git.FileHistory(ws, relPath)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]HistoryEntry, 0, len(revs))
+	for i, rev := range revs {
+		parentContent := ""
+		if i+1 < len(revs) {
+			parentContent = revs[i+1].Content
+		}
+
+		parent, err := ast.Parse(parentContent)
+		if err != nil {
+			continue
+		}
+		doc, err := ast.Parse(rev.Content)
+		if err != nil {
+			continue
+		}
+
+		changes := diff.Diff(parent, doc)
+		if len(changes) == 0 {
+			continue
+		}
+
+		entries = append(entries, HistoryEntry{
+			Commit:  rev.Hash,
+			Author:  rev.Author,
+			Date:    rev.Date,
+			Message: rev.Message,
+			Changes: changes,
+		})
+	}
+
+	var format output.Format
+	if historyJSON {
+		format = output.FormatJSON
+	} else {
+		format, _ = output.ParseFormat(historyFormat)
+		format = format.Resolve()
+	}
+
+	switch format {
+	case output.FormatJSON:
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON serialization failed: %v", err)
+		}
+		fmt.Println(string(data))
+	case output.FormatYAML:
+		data, err := yaml.Marshal(entries)
+		if err != nil {
+			return fmt.Errorf("YAML serialization failed: %v", err)
+		}
+		fmt.Print(string(data))
+	default:
+		for _, e := range entries {
+			fmt.Printf("%s  %s  %s\n", e.Commit[:min(8, len(e.Commit))], e.Date, e.Author)
+			for _, c := range e.Changes {
+				fmt.Printf("  %s\n", describeChange(c))
+			}
+		}
+	}
+
+	return nil
+}
+
+// describeChange renders a single diff.Change as a short, human-readable line.
+func describeChange(c diff.Change) string {
+	switch c.Op {
+	case diff.OpAddNote:
+		return fmt.Sprintf("added note %s: %s", c.Hash, c.Text)
+	case diff.OpRemoveNote:
+		return fmt.Sprintf("removed note %s: %s", c.Hash, c.Text)
+	case diff.OpEditNote:
+		return fmt.Sprintf("edited note %s: %s", c.Hash, c.Text)
+	case diff.OpAddTodo:
+		return fmt.Sprintf("added todo %s: %s", c.Hash, c.Text)
+	case diff.OpRemoveTodo:
+		return fmt.Sprintf("removed todo %s: %s", c.Hash, c.Text)
+	case diff.OpEditTodo:
+		return fmt.Sprintf("edited todo %s: %s", c.Hash, c.Text)
+	case diff.OpCheckTodo:
+		return fmt.Sprintf("checked off todo %s: %s", c.Hash, c.Text)
+	case diff.OpUncheckTodo:
+		return fmt.Sprintf("unchecked todo %s: %s", c.Hash, c.Text)
+	case diff.OpMoveItem:
+		return fmt.Sprintf("moved %s from %s to %s: %s", c.Hash, c.From, c.Section, c.Text)
+	case diff.OpLogEntry:
+		return fmt.Sprintf("appended log entry for %s: %s", c.Date, c.Text)
+	default:
+		return fmt.Sprintf("%s %s: %s", c.Op, c.Hash, c.Text)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hooksMarkerBegin/hooksMarkerEnd bracket the block threads writes into a
+// hook script, so install can update just that block and uninstall can
+// remove it without touching anything else chained into the same file.
+const (
+	hooksMarkerBegin = "# >>> managed by threads >>>"
+	hooksMarkerEnd   = "# <<< managed by threads <<<"
+)
+
+// hookBodies holds the shell snippet threads installs for each hook, keyed
+// by the hook's filename under .git/hooks.
+var hookBodies = map[string]string{
+	"pre-commit": `files=$(git diff --cached --name-only --diff-filter=ACM -- .threads | grep '\.md$')
+if [ -n "$files" ]; then
+    echo "$files" | threads validate --format=ci --stdin-paths || exit 1
+fi`,
+	"pre-push": `zero=0000000000000000000000000000000000000000
+while read -r local_ref local_sha remote_ref remote_sha; do
+    [ "$local_sha" = "$zero" ] && continue
+    if [ "$remote_sha" = "$zero" ]; then
+        range="$local_sha"
+    else
+        range="$remote_sha..$local_sha"
+    fi
+    files=$(git diff --name-only "$range" -- .threads | grep '\.md$')
+    if [ -n "$files" ]; then
+        echo "$files" | threads validate --format=ci --stdin-paths || exit 1
+    fi
+done`,
+}
+
+var (
+	hooksPreCommit bool
+	hooksPrePush   bool
+)
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that run threads validate",
+}
+
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install pre-commit/pre-push hooks that run threads validate",
+	Long: `Write a pre-commit and/or pre-push hook into .git/hooks that runs
+'threads validate --format=ci --stdin-paths' against only the thread
+files actually being committed or pushed, turning the validator into an
+enforcement point instead of just a reporter.
+
+With neither --pre-commit nor --pre-push, installs both. An existing
+hook is chained rather than overwritten: threads' block is appended
+after whatever's already there, delimited by a "managed by threads"
+marker so a later 'hooks uninstall' removes only that block.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksInstall,
+}
+
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove threads' git hooks",
+	Long: `Remove the "managed by threads" block from pre-commit and/or
+pre-push (both, if neither flag is given). Anything else chained into
+the same hook file is left alone; the file itself is only deleted if
+threads' block was the only content.`,
+	Args: cobra.NoArgs,
+	RunE: runHooksUninstall,
+}
+
+func init() {
+	hooksInstallCmd.Flags().BoolVar(&hooksPreCommit, "pre-commit", false, "Install the pre-commit hook")
+	hooksInstallCmd.Flags().BoolVar(&hooksPrePush, "pre-push", false, "Install the pre-push hook")
+	hooksUninstallCmd.Flags().BoolVar(&hooksPreCommit, "pre-commit", false, "Remove the pre-commit hook")
+	hooksUninstallCmd.Flags().BoolVar(&hooksPrePush, "pre-push", false, "Remove the pre-push hook")
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
+
+// selectedHooks returns the hook names --pre-commit/--pre-push selected, or
+// both if neither flag was given.
+func selectedHooks() []string {
+	if !hooksPreCommit && !hooksPrePush {
+		return []string{"pre-commit", "pre-push"}
+	}
+	var hooks []string
+	if hooksPreCommit {
+		hooks = append(hooks, "pre-commit")
+	}
+	if hooksPrePush {
+		hooks = append(hooks, "pre-push")
+	}
+	return hooks
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+	hooksDir := filepath.Join(ws, ".git", "hooks")
+
+	for _, hook := range selectedHooks() {
+		if err := installHook(hooksDir, hook); err != nil {
+			return fmt.Errorf("installing %s hook: %w", hook, err)
+		}
+		fmt.Printf("Installed %s hook.\n", hook)
+	}
+	return nil
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+	hooksDir := filepath.Join(ws, ".git", "hooks")
+
+	for _, hook := range selectedHooks() {
+		removed, err := uninstallHook(hooksDir, hook)
+		if err != nil {
+			return fmt.Errorf("removing %s hook: %w", hook, err)
+		}
+		if removed {
+			fmt.Printf("Removed %s hook.\n", hook)
+		}
+	}
+	return nil
+}
+
+// installHook writes hook's managed block into hooksDir/hook, chaining
+// after any existing content rather than overwriting it. Re-running install
+// updates the managed block in place.
+func installHook(hooksDir, hook string) error {
+	path := filepath.Join(hooksDir, hook)
+	block := hooksMarkerBegin + "\n" + hookBodies[hook] + "\n" + hooksMarkerEnd
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var content string
+	if before, after, ok := splitOnMarkers(string(existing)); ok {
+		content = before + block + "\n" + after
+	} else {
+		content = string(existing)
+		if content == "" {
+			content = "#!/bin/sh\n"
+		} else if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += block + "\n"
+	}
+
+	return os.WriteFile(path, []byte(content), 0755)
+}
+
+// uninstallHook removes hook's managed block from hooksDir/hook. It reports
+// whether anything was removed, and deletes the file outright if the
+// managed block was the file's only real content.
+func uninstallHook(hooksDir, hook string) (bool, error) {
+	path := filepath.Join(hooksDir, hook)
+
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	before, after, ok := splitOnMarkers(string(existing))
+	if !ok {
+		return false, nil
+	}
+
+	remainder := strings.TrimSpace(before + after)
+	if remainder == "" || remainder == "#!/bin/sh" {
+		return true, os.Remove(path)
+	}
+	return true, os.WriteFile(path, []byte(before+after), 0755)
+}
+
+// splitOnMarkers finds threads' managed block in content and returns the
+// text before and after it. ok is false if the block isn't present.
+func splitOnMarkers(content string) (before, after string, ok bool) {
+	start := strings.Index(content, hooksMarkerBegin)
+	if start == -1 {
+		return "", "", false
+	}
+	end := strings.Index(content, hooksMarkerEnd)
+	if end == -1 || end < start {
+		return "", "", false
+	}
+	end += len(hooksMarkerEnd)
+	// Absorb one trailing newline so repeated install/uninstall doesn't
+	// accumulate blank lines.
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:start], content[end:], true
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Inspect and maintain the on-disk thread index",
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the thread index from scratch",
+	Long: `Rebuild the on-disk thread index (.git/threads/index) from scratch.
+
+Normal commands refresh the index incrementally, re-parsing only threads
+whose mtime or size changed. Use this after bulk changes outside threads'
+own commands (a branch switch, a scripted rewrite) to force every thread
+to be re-parsed.`,
+	Args: cobra.NoArgs,
+	RunE: runIndexRebuild,
+}
+
+func init() {
+	indexCmd.AddCommand(indexRebuildCmd)
+}
+
+func runIndexRebuild(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+
+	idx, err := workspace.OpenIndex(ws)
+	if err != nil {
+		return err
+	}
+	for _, e := range idx.All() {
+		idx.Invalidate(e.Path)
+	}
+	if err := idx.Refresh(); err != nil {
+		return fmt.Errorf("rebuilding index: %w", err)
+	}
+
+	fmt.Printf("Rebuilt index: %d threads\n", len(idx.All()))
+	return nil
+}
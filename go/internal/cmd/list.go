@@ -16,14 +16,18 @@ import (
 )
 
 var (
-	listDown          *int
-	listRecursive     bool
-	listUp            *int
-	listIncludeClosed bool
-	listSearch        string
-	listStatus        string
-	listFormat        string
-	listJSON          bool
+	listDown           *int
+	listRecursive      bool
+	listUp             *int
+	listIncludeClosed  bool
+	listSearch         string
+	listStatus         string
+	listFormat         string
+	listJSON           bool
+	listAllWorktrees   bool
+	listNoIgnore       bool
+	listWorktreeShared bool
+	listSubmodules     bool
 )
 
 var listCmd = &cobra.Command{
@@ -42,7 +46,8 @@ Path resolution:
 By default shows active threads at the current level only.
 Use -d/--down to include subdirectories, -u/--up to include parent directories.
 Use -r as an alias for --down (unlimited depth).
-Use --include-closed to include resolved/terminal threads.`,
+Use --include-closed to include resolved/terminal threads.
+Use --no-ignore to disable .gitignore/.threadsignore filtering.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runList,
 }
@@ -59,6 +64,10 @@ func init() {
 	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (comma-separated)")
 	listCmd.Flags().StringVarP(&listFormat, "format", "f", "fancy", "Output format: fancy, plain, json, yaml")
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON (shorthand for --format=json)")
+	listCmd.Flags().BoolVar(&listAllWorktrees, "all-worktrees", false, "Also list threads from linked worktrees")
+	listCmd.Flags().BoolVar(&listNoIgnore, "no-ignore", false, "Don't apply .gitignore/.threadsignore rules")
+	listCmd.Flags().BoolVar(&listWorktreeShared, "worktree-shared", false, "When --down/--up searching from a linked worktree, also search the main worktree's tree")
+	listCmd.Flags().BoolVar(&listSubmodules, "include-submodules", false, "When --down searching, descend into directories registered as submodules in .gitmodules")
 }
 
 // searchDirection describes the search direction for output display.
@@ -107,6 +116,7 @@ type threadInfo struct {
 	Desc         string `json:"desc" yaml:"desc"`
 	PathAbsolute string `json:"path_absolute,omitempty" yaml:"path_absolute,omitempty"`
 	IsPwd        bool   `json:"is_pwd,omitempty" yaml:"is_pwd,omitempty"`
+	Worktree     string `json:"worktree,omitempty" yaml:"worktree,omitempty"`
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -128,7 +138,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Resolve the scope
-	scope, err := workspace.InferScope(gitRoot, pathArg)
+	scope, err := workspace.InferScope(gitRoot, pathArg, false)
 	if err != nil {
 		return err
 	}
@@ -169,6 +179,10 @@ func runList(cmd *cobra.Command, args []string) error {
 		options = options.WithUp(&depth)
 	}
 
+	options = options.WithNoIgnore(listNoIgnore)
+	options = options.WithWorktreeShared(listWorktreeShared)
+	options = options.WithIncludeSubmodules(listSubmodules)
+
 	// Track search direction for output
 	searchDir := &searchDirection{
 		hasDown:   hasDown,
@@ -191,78 +205,102 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	var results []threadInfo
 
-	for _, path := range threads {
-		t, err := thread.Parse(path)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to parse %s: %v\n", path, err)
-			continue
-		}
-
-		relPath := workspace.ParseThreadPath(gitRoot, path)
-		status := t.Status()
-		baseStatus := thread.BaseStatus(status)
-		name := thread.ExtractNameFromPath(path)
-
-		// Path filter: if not searching, only show threads at the specified level
-		if !searchDir.isSearching() {
-			if relPath != filterPath {
+	// appendThreads filters and converts threads rooted at scopeRoot into
+	// threadInfo rows. applyScopeFilter restricts to the resolved scope
+	// path; it's only meaningful for the current worktree, since --path
+	// scoping doesn't carry over to other worktrees.
+	appendThreads := func(paths []string, scopeRoot, worktreeName string, applyScopeFilter bool) {
+		for _, path := range paths {
+			t, err := thread.Parse(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to parse %s: %v\n", path, err)
 				continue
 			}
-		}
-		// Note: FindThreadsWithOptions already handles direction/depth filtering
 
-		// Status filter
-		statusFlagSet := cmd.Flags().Changed("status")
-		if statusFlagSet {
-			if listStatus == "" {
-				continue
+			relPath := workspace.ParseThreadPath(scopeRoot, path)
+			status := t.Status()
+			baseStatus := thread.BaseStatus(status)
+			name := thread.ExtractNameFromPath(path)
+
+			// Path filter: if not searching, only show threads at the specified level
+			if applyScopeFilter && !searchDir.isSearching() {
+				if relPath != filterPath {
+					continue
+				}
 			}
-			if !strings.Contains(","+listStatus+",", ","+baseStatus+",") {
-				continue
+			// Note: FindThreadsWithOptions already handles direction/depth filtering
+
+			// Status filter
+			statusFlagSet := cmd.Flags().Changed("status")
+			if statusFlagSet {
+				if listStatus == "" {
+					continue
+				}
+				if !strings.Contains(","+listStatus+",", ","+baseStatus+",") {
+					continue
+				}
+			} else {
+				if !listIncludeClosed && thread.IsTerminal(status) {
+					continue
+				}
 			}
-		} else {
-			if !listIncludeClosed && thread.IsTerminal(status) {
-				continue
+
+			// Search filter
+			if listSearch != "" {
+				searchLower := strings.ToLower(listSearch)
+				nameLower := strings.ToLower(name)
+				titleLower := strings.ToLower(t.Name())
+				descLower := strings.ToLower(t.Frontmatter.Desc)
+
+				if !strings.Contains(nameLower, searchLower) &&
+					!strings.Contains(titleLower, searchLower) &&
+					!strings.Contains(descLower, searchLower) {
+					continue
+				}
 			}
-		}
 
-		// Search filter
-		if listSearch != "" {
-			searchLower := strings.ToLower(listSearch)
-			nameLower := strings.ToLower(name)
-			titleLower := strings.ToLower(t.Name())
-			descLower := strings.ToLower(t.Frontmatter.Desc)
+			// Use title if available, else humanize name
+			title := t.Name()
+			if title == "" {
+				title = strings.ReplaceAll(name, "-", " ")
+			}
 
-			if !strings.Contains(nameLower, searchLower) &&
-				!strings.Contains(titleLower, searchLower) &&
-				!strings.Contains(descLower, searchLower) {
-				continue
+			isPwd := applyScopeFilter && relPath == pwdRel
+
+			info := threadInfo{
+				ID:       t.ID(),
+				Status:   baseStatus,
+				Path:     relPath,
+				Name:     name,
+				Title:    title,
+				Desc:     t.Frontmatter.Desc,
+				IsPwd:    isPwd,
+				Worktree: worktreeName,
 			}
-		}
 
-		// Use title if available, else humanize name
-		title := t.Name()
-		if title == "" {
-			title = strings.ReplaceAll(name, "-", " ")
+			if includeAbsolute {
+				info.PathAbsolute = path
+			}
+
+			results = append(results, info)
 		}
+	}
 
-		isPwd := relPath == pwdRel
+	appendThreads(threads, gitRoot, "", true)
 
-		info := threadInfo{
-			ID:     t.ID(),
-			Status: baseStatus,
-			Path:   relPath,
-			Name:   name,
-			Title:  title,
-			Desc:   t.Frontmatter.Desc,
-			IsPwd:  isPwd,
+	if listAllWorktrees {
+		worktrees, err := workspace.ListWorktrees(gitRoot)
+		if err != nil {
+			return fmt.Errorf("listing worktrees: %w", err)
 		}
-
-		if includeAbsolute {
-			info.PathAbsolute = path
+		for _, wt := range worktrees {
+			wtThreads, err := workspace.FindAllThreads(wt.Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to scan worktree %s: %v\n", wt.Name, err)
+				continue
+			}
+			appendThreads(wtThreads, wt.Path, wt.Name, false)
 		}
-
-		results = append(results, info)
 	}
 
 	switch format {
@@ -316,8 +354,13 @@ func outputFancy(results []threadInfo, gitRoot, filterPath, pwdRel string, searc
 	}
 
 	// Print table header
-	fmt.Printf("%-6s %-10s %-24s %s\n", "ID", "STATUS", "PATH", "NAME")
-	fmt.Printf("%-6s %-10s %-24s %s\n", "--", "------", "----", "----")
+	if listAllWorktrees {
+		fmt.Printf("%-6s %-10s %-24s %-14s %s\n", "ID", "STATUS", "PATH", "WORKTREE", "NAME")
+		fmt.Printf("%-6s %-10s %-24s %-14s %s\n", "--", "------", "----", "--------", "----")
+	} else {
+		fmt.Printf("%-6s %-10s %-24s %s\n", "ID", "STATUS", "PATH", "NAME")
+		fmt.Printf("%-6s %-10s %-24s %s\n", "--", "------", "----", "----")
+	}
 
 	for _, t := range results {
 		pathDisplay := truncate(t.Path, 22)
@@ -325,7 +368,15 @@ func outputFancy(results []threadInfo, gitRoot, filterPath, pwdRel string, searc
 		if t.IsPwd {
 			marker = " ←"
 		}
-		fmt.Printf("%-6s %-10s %-24s %s%s\n", t.ID, t.Status, pathDisplay, t.Title, marker)
+		if listAllWorktrees {
+			worktree := t.Worktree
+			if worktree == "" {
+				worktree = "(current)"
+			}
+			fmt.Printf("%-6s %-10s %-24s %-14s %s%s\n", t.ID, t.Status, pathDisplay, worktree, t.Title, marker)
+		} else {
+			fmt.Printf("%-6s %-10s %-24s %s%s\n", t.ID, t.Status, pathDisplay, t.Title, marker)
+		}
 	}
 
 	return nil
@@ -373,8 +424,13 @@ func outputPlain(results []threadInfo, gitRoot, filterPath, pwdRel string, searc
 	}
 
 	// Print table header
-	fmt.Printf("%-6s %-10s %-24s %s\n", "ID", "STATUS", "PATH", "NAME")
-	fmt.Printf("%-6s %-10s %-24s %s\n", "--", "------", "----", "----")
+	if listAllWorktrees {
+		fmt.Printf("%-6s %-10s %-24s %-14s %s\n", "ID", "STATUS", "PATH", "WORKTREE", "NAME")
+		fmt.Printf("%-6s %-10s %-24s %-14s %s\n", "--", "------", "----", "--------", "----")
+	} else {
+		fmt.Printf("%-6s %-10s %-24s %s\n", "ID", "STATUS", "PATH", "NAME")
+		fmt.Printf("%-6s %-10s %-24s %s\n", "--", "------", "----", "----")
+	}
 
 	for _, t := range results {
 		pathDisplay := truncate(t.Path, 22)
@@ -382,7 +438,15 @@ func outputPlain(results []threadInfo, gitRoot, filterPath, pwdRel string, searc
 		if t.IsPwd {
 			pwdMarker = " ← PWD"
 		}
-		fmt.Printf("%-6s %-10s %-24s %s%s\n", t.ID, t.Status, pathDisplay, t.Title, pwdMarker)
+		if listAllWorktrees {
+			worktree := t.Worktree
+			if worktree == "" {
+				worktree = "(current)"
+			}
+			fmt.Printf("%-6s %-10s %-24s %-14s %s%s\n", t.ID, t.Status, pathDisplay, worktree, t.Title, pwdMarker)
+		} else {
+			fmt.Printf("%-6s %-10s %-24s %s%s\n", t.ID, t.Status, pathDisplay, t.Title, pwdMarker)
+		}
 	}
 
 	return nil
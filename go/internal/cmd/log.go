@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -70,6 +71,7 @@ func runLog(cmd *cobra.Command, args []string) error {
 	if err := t.Write(); err != nil {
 		return err
 	}
+	workspace.InvalidateIndex(ws, file)
 
 	fmt.Printf("Logged to: %s\n", file)
 
@@ -82,7 +84,7 @@ func runLog(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
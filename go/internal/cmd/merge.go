@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var (
+	mergeOurs   bool
+	mergeTheirs bool
+	mergeBase   bool
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <id>",
+	Short: "Resolve a merge conflict in a thread file",
+	Long: `Resolve a git merge conflict in a thread file.
+
+By default, merges frontmatter field-by-field (status follows the lattice
+resolved > blocked > active > idea) and unions the Log section's entries by
+timestamp, deduplicating identical lines. Pass --ours, --theirs, or --base
+to skip merging and take one side outright, mirroring 'git checkout
+--ours/--theirs/--merge-base'. At most one of these may be given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().BoolVar(&mergeOurs, "ours", false, "Take our side as-is")
+	mergeCmd.Flags().BoolVar(&mergeTheirs, "theirs", false, "Take their side as-is")
+	mergeCmd.Flags().BoolVar(&mergeBase, "base", false, "Take the common ancestor as-is")
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	if err := validateStageFlags(mergeBase, mergeOurs, mergeTheirs); err != nil {
+		return err
+	}
+
+	ws := getWorkspace()
+	ref := args[0]
+
+	file, err := workspace.FindByRef(ws, ref)
+	if err != nil {
+		return err
+	}
+
+	if err := resolveConflict(ws, file, mergeBase, mergeOurs, mergeTheirs); err != nil {
+		return err
+	}
+
+	fmt.Printf("Merged: %s\n", file)
+	fmt.Println("Note: Staged. Finish the merge commit with 'git commit' when ready.")
+	return nil
+}
+
+// resolveConflict merges (or, if a stage flag is set, picks) the three
+// sides of a conflicted thread file and stages the result. Shared by
+// `threads merge` and the --ours/--theirs/--base flags on `threads resolve`.
+func resolveConflict(ws, file string, base, ours, theirs bool) error {
+	if err := validateStageFlags(base, ours, theirs); err != nil {
+		return err
+	}
+
+	relPath, _ := filepath.Rel(ws, file)
+
+	baseContent, err := git.ReadStage(ws, relPath, git.StageBase)
+	if err != nil {
+		return err
+	}
+	oursContent, err := git.ReadStage(ws, relPath, git.StageOurs)
+	if err != nil {
+		return err
+	}
+	theirsContent, err := git.ReadStage(ws, relPath, git.StageTheirs)
+	if err != nil {
+		return err
+	}
+
+	var resolved string
+	switch {
+	case base:
+		resolved = baseContent
+	case ours:
+		resolved = oursContent
+	case theirs:
+		resolved = theirsContent
+	default:
+		resolved, err = mergeThreadContent(baseContent, oursContent, theirsContent)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(file, []byte(resolved), 0644); err != nil {
+		return err
+	}
+
+	return git.Add(ws, relPath)
+}
+
+// validateStageFlags rejects more than one of --base/--ours/--theirs being
+// set at once, the same mutual-exclusion rule git-lfs applies to its
+// checkout stage selection.
+func validateStageFlags(base, ours, theirs bool) error {
+	count := 0
+	for _, v := range []bool{base, ours, theirs} {
+		if v {
+			count++
+		}
+	}
+	if count > 1 {
+		return fmt.Errorf("only one of --base, --ours, --theirs may be given")
+	}
+	return nil
+}
+
+// mergeThreadContent merges frontmatter and the Log section structurally,
+// and otherwise falls back to theirs for anything it doesn't understand
+// (conflict markers left by git in, say, the Body section).
+func mergeThreadContent(base, ours, theirs string) (string, error) {
+	baseFM, _ := parseFrontmatterOnly(base)
+	oursFM, err := parseFrontmatterOnly(ours)
+	if err != nil {
+		return "", fmt.Errorf("parsing our frontmatter: %w", err)
+	}
+	theirsFM, err := parseFrontmatterOnly(theirs)
+	if err != nil {
+		return "", fmt.Errorf("parsing their frontmatter: %w", err)
+	}
+
+	merged := thread.MergeFrontmatter(baseFM, oursFM, theirsFM)
+
+	oursLog := thread.ExtractSection(ours, "Log")
+	theirsLog := thread.ExtractSection(theirs, "Log")
+	mergedLog := thread.MergeLog(oursLog, theirsLog)
+
+	// Body: prefer whichever side actually changed it from base, defaulting
+	// to theirs (conflict markers, if any, are left for the user to see).
+	body := theirs
+	if base != "" {
+		if thread.ExtractSection(ours, "Body") != thread.ExtractSection(base, "Body") {
+			body = ours
+		}
+	}
+	bodySection := thread.ExtractSection(body, "Body")
+
+	out := renderFrontmatter(merged)
+	out += "\n## Body\n\n" + bodySection + "\n\n"
+	out += "## Log\n\n" + mergedLog
+	return out, nil
+}
+
+func parseFrontmatterOnly(content string) (thread.Frontmatter, error) {
+	var fm thread.Frontmatter
+	if content == "" {
+		return fm, nil
+	}
+	if len(content) < 4 || content[:4] != "---\n" {
+		return fm, fmt.Errorf("missing frontmatter delimiter")
+	}
+	end := indexAfter(content)
+	if end == -1 {
+		return fm, fmt.Errorf("unclosed frontmatter")
+	}
+	err := yaml.Unmarshal([]byte(content[4:end]), &fm)
+	return fm, err
+}
+
+func indexAfter(content string) int {
+	idx := -1
+	for i := 4; i+4 <= len(content); i++ {
+		if content[i:i+4] == "\n---" {
+			idx = i + 1
+			break
+		}
+	}
+	return idx
+}
+
+func renderFrontmatter(fm thread.Frontmatter) string {
+	out, _ := yaml.Marshal(&fm)
+	return "---\n" + string(out) + "---\n"
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread"
+)
+
+// mergeDriverName is the name git.attributes/git config reference for
+// threads' custom merge driver, e.g. "[merge "threads-md"]".
+const mergeDriverName = "threads-md"
+
+var mergeDriverCmd = &cobra.Command{
+	Use:    "merge-driver <base> <ours> <theirs> <path>",
+	Short:  "Git merge driver plumbing for thread markdown files (see install-merge-driver)",
+	Hidden: true,
+	Args:   cobra.ExactArgs(4),
+	RunE:   runMergeDriver,
+}
+
+var installMergeDriverCmd = &cobra.Command{
+	Use:   "install-merge-driver",
+	Short: "Register threads' section-aware merge driver for .threads/*.md",
+	Long: `Write the gitattributes entry and git config needed for git to run
+'threads merge-driver' on conflicting thread files instead of its normal
+line-based 3-way merge.
+
+This adds a line to .gitattributes (creating it if needed):
+
+  .threads/**/*.md merge=threads-md
+
+and sets merge.threads-md.driver in the repository's local git config.
+Run it once per clone; the registration isn't itself tracked by git.`,
+	Args: cobra.NoArgs,
+	RunE: runInstallMergeDriver,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(installMergeDriverCmd)
+}
+
+// runMergeDriver is what git invokes as `merge.threads-md.driver`: %O %A %B
+// %P, i.e. the common ancestor, our version, their version (all temp file
+// paths), and the real path being merged. Per the gitattributes merge
+// driver protocol, the result replaces %A's content regardless of outcome;
+// the exit status only tells git whether the merge was clean.
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	basePath, oursPath, theirsPath, path := args[0], args[1], args[2], args[3]
+
+	base, err := os.ReadFile(basePath)
+	if err != nil {
+		return fmt.Errorf("reading base version: %w", err)
+	}
+	ours, err := os.ReadFile(oursPath)
+	if err != nil {
+		return fmt.Errorf("reading our version: %w", err)
+	}
+	theirs, err := os.ReadFile(theirsPath)
+	if err != nil {
+		return fmt.Errorf("reading their version: %w", err)
+	}
+
+	merged, conflicts, err := thread.Merge(base, ours, theirs)
+	if err != nil {
+		return fmt.Errorf("merging %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(oursPath, merged, 0644); err != nil {
+		return fmt.Errorf("writing merged result: %w", err)
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%s: %d conflict(s) need manual review", path, len(conflicts))
+	}
+	return nil
+}
+
+func runInstallMergeDriver(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+
+	if err := addGitattributesEntry(ws); err != nil {
+		return err
+	}
+
+	driverCmd := "threads merge-driver %O %A %B %P"
+	if err := git.ConfigSet(ws, "merge."+mergeDriverName, "name", "threads section-aware merge"); err != nil {
+		return fmt.Errorf("setting merge driver name: %w", err)
+	}
+	if err := git.ConfigSet(ws, "merge."+mergeDriverName, "driver", driverCmd); err != nil {
+		return fmt.Errorf("setting merge driver: %w", err)
+	}
+
+	fmt.Println("Installed merge driver: merge." + mergeDriverName + ".driver")
+	fmt.Println("Note: .gitattributes was updated; commit it so collaborators get the same driver registration.")
+	return nil
+}
+
+// addGitattributesEntry appends the thread-file merge attribute to
+// .gitattributes at the workspace root, creating the file if needed and
+// leaving it untouched if the entry is already present.
+func addGitattributesEntry(ws string) error {
+	path := filepath.Join(ws, ".gitattributes")
+	entry := ".threads/**/*.md merge=" + mergeDriverName
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading .gitattributes: %w", err)
+	}
+
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return nil
+		}
+	}
+
+	content := string(existing)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += entry + "\n"
+
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var migrateHashesCommit bool
+
+var migrateHashesCmd = &cobra.Command{
+	Use:   "migrate-hashes",
+	Short: "Rewrite every note and todo item's hash to the content-addressed scheme",
+	Long: `Re-tag every Note and TodoItem across the workspace with its
+content-addressed hash (see thread.ItemID) instead of whatever hash it
+was created with, growing each tag's prefix only as far as needed to stay
+unique among all items scanned.
+
+Run this once after upgrading. Only files with a hash to change are
+rewritten; with --commit, they all land in a single commit so history
+stays intact. References baked into commit messages or
+external notes that point at an old hash won't resolve afterwards.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateHashes,
+}
+
+func init() {
+	migrateHashesCmd.Flags().BoolVar(&migrateHashesCommit, "commit", false, "Commit the rewritten files")
+	rootCmd.AddCommand(migrateHashesCmd)
+}
+
+func runMigrateHashes(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+
+	paths, err := workspace.FindAllThreads(ws)
+	if err != nil {
+		return err
+	}
+
+	idx := thread.BuildIndex(paths)
+
+	var changed []string
+	for _, path := range paths {
+		rewritten, err := migrateFileHashes(path, idx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if rewritten {
+			changed = append(changed, path)
+		}
+	}
+
+	if len(changed) == 0 {
+		fmt.Println("No item hashes needed migrating.")
+		return nil
+	}
+
+	fmt.Printf("Migrated item hashes in %d thread(s).\n", len(changed))
+	for _, path := range changed {
+		relPath, _ := filepath.Rel(ws, path)
+		fmt.Printf("  %s\n", relPath)
+	}
+
+	if !migrateHashesCommit {
+		fmt.Println("Run with --commit to commit these changes.")
+		return nil
+	}
+
+	var relPaths []string
+	for _, path := range changed {
+		relPath, err := filepath.Rel(ws, path)
+		if err != nil {
+			relPath = path
+		}
+		relPaths = append(relPaths, relPath)
+	}
+	return git.Commit(ws, relPaths, "threads: migrate item hashes to content-addressed scheme")
+}
+
+// migrateFileHashes rewrites every Note's and TodoItem's hash in path to
+// the content-addressed scheme, growing each one's prefix against idx, and
+// writes the file back if anything changed. It reports whether it did.
+func migrateFileHashes(path string, idx *thread.Index) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	doc, err := ast.Parse(string(content))
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for _, sec := range doc.Sections {
+		for _, b := range sec.Blocks {
+			text, hash, ok := itemHash(b)
+			if !ok {
+				continue
+			}
+
+			full := thread.NewItemID(sec.Name, text).Full()
+			n := idx.ShortLen(full)
+			if full[:n] == hash {
+				continue
+			}
+
+			if err := doc.GrowItemHash(sec.Name, hash, n); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	return true, os.WriteFile(path, []byte(doc.Render()), 0644)
+}
+
+// itemHash returns a Note's or TodoItem's text and current hash, and true,
+// or "", "", false for block types that don't carry one.
+func itemHash(b ast.Block) (text, hash string, ok bool) {
+	switch v := b.(type) {
+	case *ast.Note:
+		return v.Text, v.Hash, true
+	case *ast.TodoItem:
+		return v.Text, v.Hash, true
+	default:
+		return "", "", false
+	}
+}
@@ -8,12 +8,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
 
 var (
 	moveCommit bool
 	moveMsg    string
+	moveShared bool
 )
 
 var moveCmd = &cobra.Command{
@@ -34,6 +36,7 @@ Path resolution for new-path:
 func init() {
 	moveCmd.Flags().BoolVar(&moveCommit, "commit", false, "Commit after moving")
 	moveCmd.Flags().StringVarP(&moveMsg, "m", "m", "", "Commit message")
+	moveCmd.Flags().BoolVar(&moveShared, "shared", false, "In a linked worktree, move the thread under the main worktree so every worktree sees it")
 }
 
 func runMove(cmd *cobra.Command, args []string) error {
@@ -48,7 +51,7 @@ func runMove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Resolve destination scope
-	scope, err := workspace.InferScope(gitRoot, newPath)
+	scope, err := workspace.InferScope(gitRoot, newPath, moveShared)
 	if err != nil {
 		return fmt.Errorf("invalid path '%s': %v", newPath, err)
 	}
@@ -69,6 +72,8 @@ func runMove(cmd *cobra.Command, args []string) error {
 	if err := os.Rename(srcFile, destFile); err != nil {
 		return fmt.Errorf("moving file: %w", err)
 	}
+	workspace.InvalidateIndex(gitRoot, srcFile)
+	workspace.InvalidateIndex(gitRoot, destFile)
 
 	relDest := workspace.PathRelativeToGitRoot(gitRoot, destFile)
 	fmt.Printf("Moved to %s\n", scope.LevelDesc)
@@ -89,7 +94,7 @@ func runMove(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println("Note: Changes are local. Push with 'git push' when ready.")
 	} else {
-		fmt.Println("Note: Use --commit to commit this move")
+		fmt.Println(i18n.T("Note: Use --commit to commit this move"))
 	}
 
 	return nil
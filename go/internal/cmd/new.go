@@ -13,6 +13,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/output"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
@@ -26,6 +27,7 @@ var (
 	newMsg    string
 	newFormat string
 	newJSON   bool
+	newShared bool
 )
 
 type newOutput struct {
@@ -57,6 +59,7 @@ func init() {
 	newCmd.Flags().StringVarP(&newMsg, "m", "m", "", "Commit message")
 	newCmd.Flags().StringVarP(&newFormat, "format", "f", "fancy", "Output format (fancy, plain, json, yaml)")
 	newCmd.Flags().BoolVar(&newJSON, "json", false, "Output as JSON (shorthand for --format=json)")
+	newCmd.Flags().BoolVar(&newShared, "shared", false, "In a linked worktree, file the thread under the main worktree so every worktree sees it")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
@@ -85,12 +88,12 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	if !thread.IsValidStatus(newStatus) {
-		return fmt.Errorf("invalid status '%s'. Must be one of: idea, planning, active, blocked, paused, resolved, superseded, deferred, rejected", newStatus)
+		return fmt.Errorf("%s", i18n.T("invalid status '%s'. Must be one of: idea, planning, active, blocked, paused, resolved, superseded, deferred, rejected", newStatus))
 	}
 
 	// Warn if no description provided
 	if newDesc == "" {
-		fmt.Fprintln(os.Stderr, "Warning: No --desc provided. Add one with: threads update <id> --desc \"...\"")
+		fmt.Fprintln(os.Stderr, i18n.T("Warning: No --desc provided. Add one with: threads update <id> --desc \"...\""))
 	}
 
 	// Slugify title
@@ -111,13 +114,15 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// Determine scope using new path resolution
-	scope, err := workspace.InferScope(gitRoot, pathArg)
+	scope, err := workspace.InferScope(gitRoot, pathArg, newShared)
 	if err != nil {
 		return err
 	}
 
-	// Generate ID
-	id, err := workspace.GenerateID(gitRoot)
+	// Generate ID. The seed is only used under id_source: content, where it
+	// makes the ID reproducible from the thread's title and creation time.
+	seed := title + "@" + time.Now().Format(time.RFC3339Nano)
+	id, err := workspace.GenerateIDForSeed(gitRoot, seed)
 	if err != nil {
 		return err
 	}
@@ -165,6 +170,7 @@ func runNew(cmd *cobra.Command, args []string) error {
 	if err := os.WriteFile(threadPath, []byte(sb.String()), 0644); err != nil {
 		return fmt.Errorf("writing thread file: %w", err)
 	}
+	workspace.InvalidateIndex(gitRoot, threadPath)
 
 	// Display path relative to git root
 	relPath := workspace.PathRelativeToGitRoot(gitRoot, threadPath)
@@ -175,7 +181,7 @@ func runNew(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  → %s\n", relPath)
 
 		if newBody == "" {
-			fmt.Fprintln(os.Stderr, "Hint: Add body with: echo \"content\" | threads body", id, "--set")
+			fmt.Fprintln(os.Stderr, i18n.T("Hint: Add body with: echo \"content\" | threads body %s --set", id))
 		}
 	case output.JSON:
 		out := newOutput{
@@ -211,7 +217,7 @@ func runNew(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else if fmt_ == output.Fancy || fmt_ == output.Plain {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", id, id)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", id, id))
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -57,8 +58,13 @@ func runNote(cmd *cobra.Command, args []string) error {
 		}
 		text := args[2]
 
+		var idx *thread.Index
+		if paths, err := workspace.FindAllThreads(ws); err == nil {
+			idx = thread.BuildIndex(paths)
+		}
+
 		var hash string
-		t.Content, hash = thread.AddNote(t.Content, text)
+		t.Content, hash = thread.AddNote(t.Content, text, idx)
 
 		// Add log entry
 		logEntry = fmt.Sprintf("Added note: %s", text)
@@ -136,7 +142,7 @@ func runNote(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -69,7 +70,7 @@ func runReopen(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
@@ -2,32 +2,52 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
 
 var (
-	resolveCommit bool
-	resolveMsg    string
+	resolveCommit   bool
+	resolveMsg      string
+	resolveWorktree bool
+	resolveOurs     bool
+	resolveTheirs   bool
+	resolveBase     bool
 )
 
 var resolveCmd = &cobra.Command{
 	Use:   "resolve <id>",
 	Short: "Mark thread resolved",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runResolve,
+	Long: `Mark thread resolved.
+
+If the thread file has an unresolved git merge conflict, pass --ours,
+--theirs, or --base to take one side outright, or omit all three to merge
+frontmatter and Log entries structurally (see 'threads merge --help').
+At most one of --ours/--theirs/--base may be given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runResolve,
 }
 
 func init() {
 	resolveCmd.Flags().BoolVar(&resolveCommit, "commit", false, "Commit after resolving")
 	resolveCmd.Flags().StringVarP(&resolveMsg, "m", "m", "", "Commit message")
+	resolveCmd.Flags().BoolVar(&resolveWorktree, "worktree", false, "Commit inside an isolated git worktree")
+	resolveCmd.Flags().BoolVar(&resolveOurs, "ours", false, "If conflicted, take our side outright")
+	resolveCmd.Flags().BoolVar(&resolveTheirs, "theirs", false, "If conflicted, take their side outright")
+	resolveCmd.Flags().BoolVar(&resolveBase, "base", false, "If conflicted, take the common ancestor outright")
 }
 
 func runResolve(cmd *cobra.Command, args []string) error {
+	if err := validateStageFlags(resolveBase, resolveOurs, resolveTheirs); err != nil {
+		return err
+	}
+
 	ws := getWorkspace()
 	ref := args[0]
 
@@ -36,6 +56,14 @@ func runResolve(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	conflictRel, _ := filepath.Rel(ws, file)
+	oursStage, _ := git.ReadStage(ws, conflictRel, git.StageOurs)
+	if resolveOurs || resolveTheirs || resolveBase || oursStage != "" {
+		if err := resolveConflict(ws, file, resolveBase, resolveOurs, resolveTheirs); err != nil {
+			return err
+		}
+	}
+
 	t, err := thread.Parse(file)
 	if err != nil {
 		return err
@@ -55,18 +83,23 @@ func runResolve(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Resolved: %s → resolved (%s)\n", oldStatus, file)
+	fmt.Println(i18n.T("Resolved: %s → resolved (%s)", oldStatus, file))
 
 	if resolveCommit {
 		msg := resolveMsg
 		if msg == "" {
 			msg = git.GenerateCommitMessage(ws, []string{file})
 		}
-		if err := git.AutoCommit(ws, file, msg); err != nil {
+		if resolveWorktree {
+			relPath, _ := filepath.Rel(ws, file)
+			if err := commitViaWorktree(ws, []string{relPath}, msg); err != nil {
+				return err
+			}
+		} else if err := git.AutoCommit(ws, file, msg); err != nil {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
@@ -1,15 +1,26 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
 
 var ws string
+var gitBackend string
+var locale string
+var verbose bool
+var timeoutSec int
+var cancelCtx context.CancelFunc
 
 var rootCmd = &cobra.Command{
 	Use:   "threads",
@@ -22,11 +33,46 @@ exploration, or decision.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := i18n.SetLocale(i18n.ResolveLocale(locale)); err != nil {
+			return err
+		}
+
+		backend := git.Backend(gitBackend)
+		if !cmd.Flags().Changed("git-backend") {
+			backend = git.BackendFromEnv()
+		}
+		if err := git.SetBackend(backend); err != nil {
+			return err
+		}
+
 		var err error
 		ws, err = workspace.Find()
 		if err != nil {
 			return fmt.Errorf("workspace not found: %w", err)
 		}
+
+		ctx := context.Background()
+		if timeoutSec > 0 {
+			ctx, cancelCtx = context.WithTimeout(ctx, time.Duration(timeoutSec)*time.Second)
+		}
+		git.SetContext(ctx)
+
+		if verbose {
+			git.LogFn = func(entry git.LogEntry) {
+				status := "ok"
+				if entry.Err != nil {
+					status = "failed"
+				}
+				fmt.Fprintf(os.Stderr, "+ git %s (%s, %s)\n", strings.Join(entry.Argv, " "), entry.Duration.Round(time.Millisecond), status)
+			}
+		}
+
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if cancelCtx != nil {
+			cancelCtx()
+		}
 		return nil
 	},
 }
@@ -36,6 +82,13 @@ func Execute() error {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&gitBackend, "git-backend", string(git.BackendGoGit),
+		"Git backend to use: gogit (embedded, no git binary required) or exec (shell out to git; requires a binary built with -tags cliexec). Also settable via THREADS_GIT_BACKEND.")
+	rootCmd.PersistentFlags().StringVar(&locale, "locale", "",
+		"Locale for command output, e.g. de. Also settable via THREADS_LANG (falls back to LC_ALL/LC_MESSAGES/LANG).")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Trace git invocations (argv, duration, outcome) to stderr")
+	rootCmd.PersistentFlags().IntVar(&timeoutSec, "timeout", 0, "Abort git operations after N seconds (0 = no timeout)")
+
 	// Workspace operations
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(newCmd)
@@ -44,6 +97,10 @@ func init() {
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(gitCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(worktreeCmd)
+	rootCmd.AddCommand(indexCmd)
 
 	// Single-thread operations
 	rootCmd.AddCommand(readCmd)
@@ -55,8 +112,12 @@ func init() {
 	rootCmd.AddCommand(todoCmd)
 	rootCmd.AddCommand(logCmd)
 	rootCmd.AddCommand(resolveCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(blameCmd)
 	rootCmd.AddCommand(reopenCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(batchCmd)
 }
 
 // getWorkspace returns the cached workspace path
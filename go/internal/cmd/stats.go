@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/output"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
@@ -115,7 +116,7 @@ func runStats(cmd *cobra.Command, args []string) error {
 	}
 
 	// Resolve the scope
-	scope, err := workspace.InferScope(gitRoot, pathArg)
+	scope, err := workspace.InferScope(gitRoot, pathArg, false)
 	if err != nil {
 		return err
 	}
@@ -170,25 +171,30 @@ func runStats(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	counts := make(map[string]int)
-	total := 0
-
+	// Path filter: if not searching, only count threads at the specified level
+	var filtered []string
 	for _, path := range threads {
 		relPath := workspace.ParseThreadPath(gitRoot, path)
-
-		// Path filter: if not searching, only count threads at the specified level
-		if !searchDir.isSearching() {
-			if relPath != filterPath {
-				continue
-			}
-		}
-
-		t, err := thread.Parse(path)
-		if err != nil {
+		if !searchDir.isSearching() && relPath != filterPath {
 			continue
 		}
+		filtered = append(filtered, path)
+	}
+
+	// Only the status field is needed here, so scan frontmatter only (via a
+	// worker pool, with results cached by mtime) rather than fully parsing
+	// every thread.
+	cache := thread.LoadStatusCache(gitRoot)
+	statuses := thread.QuickStatusAll(filtered, cache)
+	if err := cache.Save(); err != nil {
+		fmt.Printf("WARNING: writing threads-cache.json failed: %v\n", err)
+	}
+
+	counts := make(map[string]int)
+	total := 0
 
-		status := t.BaseStatus()
+	for _, status := range statuses {
+		status = thread.BaseStatus(status)
 		if status == "" {
 			status = "(none)"
 		}
@@ -228,7 +234,7 @@ func statsOutputFancy(sorted []sortedCount, total int, filterPath string, search
 
 	searchSuffix := searchDir.description()
 
-	fmt.Printf("Stats for threads in %s%s\n", pathDesc, searchSuffix)
+	fmt.Println(i18n.T("Stats for threads in %s%s", pathDesc, searchSuffix))
 	fmt.Println()
 
 	if total == 0 {
@@ -263,7 +269,7 @@ func statsOutputPlain(sorted []sortedCount, total int, gitRoot, filterPath strin
 
 	searchSuffix := searchDir.description()
 
-	fmt.Printf("Stats for threads in %s%s\n", pathDesc, searchSuffix)
+	fmt.Println(i18n.T("Stats for threads in %s%s", pathDesc, searchSuffix))
 	fmt.Println()
 
 	if total == 0 {
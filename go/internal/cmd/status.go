@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -51,6 +52,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if err := t.Write(); err != nil {
 		return err
 	}
+	workspace.InvalidateIndex(ws, file)
 
 	fmt.Printf("Status changed: %s → %s (%s)\n", oldStatus, newStatus, file)
 
@@ -63,7 +65,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+)
+
+const defaultSyncRefspec = "refs/heads/*:refs/threads/*"
+
+var (
+	syncRemote  string
+	syncRefspec string
+	syncPull    bool
+	syncPush    bool
+	syncDryRun  bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Push/pull .threads/ history across remotes",
+	Long: `Share thread history between machines and collaborators without
+touching normal branches.
+
+By default, threads are synced under refs/threads/* (see --refspec), so
+'git push'/'git pull' on your normal branches are unaffected. The remote
+defaults to --remote, then the '[threads] remote = ...' entry in git
+config, then "origin".
+
+Requires --pull and/or --push.`,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncRemote, "remote", "", "Remote to sync with (default: [threads] remote config, else origin)")
+	syncCmd.Flags().StringVar(&syncRefspec, "refspec", defaultSyncRefspec, "Refspec to sync")
+	syncCmd.Flags().BoolVar(&syncPull, "pull", false, "Fetch the refspec from the remote")
+	syncCmd.Flags().BoolVar(&syncPush, "push", false, "Push the refspec to the remote")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Report what would be done without fetching or pushing")
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if !syncPull && !syncPush {
+		return fmt.Errorf("specify --pull and/or --push")
+	}
+
+	ws := getWorkspace()
+
+	remote := syncRemote
+	if remote == "" {
+		if cfgRemote, ok := git.ConfigGet(ws, "threads", "remote"); ok {
+			remote = cfgRemote
+		}
+	}
+	if remote == "" {
+		remote = "origin"
+	}
+
+	if syncDryRun {
+		if syncPull {
+			fmt.Printf("Would fetch %s from %s\n", syncRefspec, remote)
+		}
+		if syncPush {
+			fmt.Printf("Would push %s to %s\n", syncRefspec, remote)
+		}
+		return nil
+	}
+
+	if syncPull {
+		before := threadConflictSnapshot(ws)
+		if err := git.FetchRefspec(ws, remote, syncRefspec); err != nil {
+			return fmt.Errorf("sync: fetch failed: %w", err)
+		}
+		fmt.Printf("Fetched %s from %s\n", syncRefspec, remote)
+		reportThreadConflicts(ws, before)
+	}
+
+	if syncPush {
+		if err := git.PushRefspec(ws, remote, syncRefspec); err != nil {
+			return fmt.Errorf("sync: push failed: %w", err)
+		}
+		fmt.Printf("Pushed %s to %s\n", syncRefspec, remote)
+	}
+
+	return nil
+}
+
+// threadConflictSnapshot records the current HEAD, used to diff against
+// after a fetch to report which thread files the fetch touched.
+func threadConflictSnapshot(ws string) string {
+	head, err := git.ResolveCommit(ws, "HEAD")
+	if err != nil {
+		return ""
+	}
+	return head
+}
+
+// reportThreadConflicts lists .threads/ files that differ between the
+// pre-fetch HEAD and the freshly fetched FETCH_HEAD, best-effort: a fetch
+// refspec that doesn't map the current branch simply yields no report.
+func reportThreadConflicts(ws, beforeHead string) {
+	if beforeHead == "" {
+		return
+	}
+	changed, err := git.ChangedThreadFiles(ws, beforeHead, "FETCH_HEAD")
+	if err != nil || len(changed) == 0 {
+		return
+	}
+	fmt.Println("Changed thread files since last sync:")
+	for _, f := range changed {
+		fmt.Printf("  %s\n", f)
+	}
+}
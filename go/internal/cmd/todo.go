@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/i18n"
 	"git.zib.de/cspiegel/threads/internal/thread"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
@@ -56,8 +57,13 @@ func runTodo(cmd *cobra.Command, args []string) error {
 		}
 		text := args[2]
 
+		var idx *thread.Index
+		if paths, err := workspace.FindAllThreads(ws); err == nil {
+			idx = thread.BuildIndex(paths)
+		}
+
 		var hash string
-		t.Content, hash = thread.AddTodoItem(t.Content, text)
+		t.Content, hash = thread.AddTodoItem(t.Content, text, idx)
 
 		fmt.Printf("Added to Todo: %s (id: %s)\n", text, hash)
 
@@ -147,7 +153,7 @@ func runTodo(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		fmt.Printf("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.\n", ref, ref)
+		fmt.Println(i18n.T("Note: Thread %s has uncommitted changes. Use 'threads commit %s' when ready.", ref, ref))
 	}
 
 	return nil
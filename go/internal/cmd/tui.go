@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"git.zib.de/cspiegel/threads/internal/tui"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive browser for threads",
+	Long: `Launch an interactive, three-pane browser over threads:
+
+  left   - .threads/ scopes in this repository
+  middle - threads at the selected scope
+  right  - the selected thread's rendered content
+
+Keys: tab switch pane, ↑/↓ or j/k move, r resolve, c commit, / cycle status
+filter, q quit.`,
+	RunE: runTUI,
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	ws := getWorkspace()
+
+	m, err := tui.NewModel(ws)
+	if err != nil {
+		return fmt.Errorf("starting tui: %w", err)
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
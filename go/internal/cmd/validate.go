@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -11,12 +16,14 @@ import (
 
 	"git.zib.de/cspiegel/threads/internal/output"
 	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/validate"
 	"git.zib.de/cspiegel/threads/internal/workspace"
 )
 
 var (
-	validateFormat string
-	validateJSON   bool
+	validateFormat     string
+	validateJSON       bool
+	validateStdinPaths bool
 )
 
 var validateCmd = &cobra.Command{
@@ -27,30 +34,84 @@ var validateCmd = &cobra.Command{
 }
 
 func init() {
-	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "fancy", "Output format (fancy, plain, json, yaml)")
+	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "fancy", "Output format (fancy, plain, json, yaml, ci, junit, sarif)")
 	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "Output as JSON (shorthand for --format=json)")
+	validateCmd.Flags().BoolVar(&validateStdinPaths, "stdin-paths", false, "Read a newline-separated file list from stdin instead of walking the workspace")
+}
+
+// lineIssue is a validation issue with the rule, severity, and line it
+// applies to, for renderers (--format=ci, --format=junit, --format=sarif)
+// that annotate a specific line rather than just listing a message.
+type lineIssue struct {
+	RuleID   string
+	Message  string
+	Line     int
+	Severity validate.Severity
 }
 
 type validationResult struct {
 	Path   string   `json:"path" yaml:"path"`
 	Valid  bool     `json:"valid" yaml:"valid"`
 	Issues []string `json:"issues" yaml:"issues"`
+
+	// lineIssues mirrors Issues with rule/line/severity. Unexported so
+	// it's invisible to the json/yaml output (those predate the rules
+	// engine); only the ci, junit, and sarif renderers consult it.
+	lineIssues []lineIssue
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	// Determine output format
+	// Determine output format. --format=ci and --format=junit aren't part
+	// of output.Format (they're CI-system renderers, not terminal/data
+	// formats), so they're handled as a separate path below. Absent an
+	// explicit --format/--json, auto-detect a CI environment.
+	renderFormat := ""
 	var fmt_ output.Format
 	if validateJSON {
 		fmt_ = output.FormatJSON
 	} else {
-		parsed, _ := output.ParseFormat(validateFormat)
-		fmt_ = parsed.Resolve()
+		switch validateFormat {
+		case "ci", "junit", "sarif":
+			renderFormat = validateFormat
+		default:
+			parsed, _ := output.ParseFormat(validateFormat)
+			fmt_ = parsed.Resolve()
+		}
+	}
+	if renderFormat == "" && !validateJSON && !cmd.Flags().Changed("format") {
+		switch {
+		case os.Getenv("GITHUB_ACTIONS") == "true":
+			renderFormat = "ci"
+		case os.Getenv("GITLAB_CI") == "true":
+			renderFormat = "junit"
+		}
 	}
 
 	ws := getWorkspace()
 	var files []string
 
-	if len(args) > 0 {
+	switch {
+	case validateStdinPaths:
+		// Pre-commit/pre-push hooks already know exactly which files
+		// changed (git diff --cached / the pre-push ref list); reading
+		// that list here skips walking the whole workspace, which matters
+		// once a repo has thousands of threads.
+		scanner := bufio.NewScanner(cmd.InOrStdin())
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			path := line
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(ws, path)
+			}
+			files = append(files, path)
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("reading stdin paths: %w", err)
+		}
+	case len(args) > 0:
 		target := args[0]
 		// Check if it's a file path
 		absPath := target
@@ -58,7 +119,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 			absPath = filepath.Join(ws, target)
 		}
 		files = []string{absPath}
-	} else {
+	default:
 		var err error
 		files, err = workspace.FindAllThreads(ws)
 		if err != nil {
@@ -66,40 +127,88 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Parse every file being reported on.
+	parsed := make(map[string]*thread.Thread, len(files))
+	parseErrors := make(map[string]error)
+	for _, file := range files {
+		t, err := thread.Parse(file)
+		if err != nil {
+			parseErrors[file] = err
+			continue
+		}
+		parsed[file] = t
+	}
+
+	// Corpus-aware rules (duplicate-title, broken-cross-reference) need
+	// every thread in the workspace, not just the ones being reported on:
+	// --stdin-paths and a single positional arg both narrow files down to
+	// less than the whole tree, and a reference to an untouched,
+	// pre-existing thread is still valid.
+	corpusPaths, err := workspace.FindAllThreads(ws)
+	if err != nil {
+		return err
+	}
+	var corpus []*thread.Thread
+	for _, p := range corpusPaths {
+		if t, ok := parsed[p]; ok {
+			corpus = append(corpus, t)
+			continue
+		}
+		if t, err := thread.Parse(p); err == nil {
+			corpus = append(corpus, t)
+		}
+	}
+
+	runner, err := validate.NewRunner(ws, corpus)
+	if err != nil {
+		return err
+	}
+
 	var results []validationResult
 	errorCount := 0
 
 	for _, file := range files {
 		relPath, _ := filepath.Rel(ws, file)
-		t, err := thread.Parse(file)
 
 		var issues []string
+		var lineIssues []lineIssue
 
-		if err != nil {
-			issues = append(issues, fmt.Sprintf("parse error: %v", err))
+		if parseErr, failed := parseErrors[file]; failed {
+			msg := fmt.Sprintf("parse error: %v", parseErr)
+			issues = append(issues, msg)
+			lineIssues = append(lineIssues, lineIssue{RuleID: "parse-error", Message: msg, Line: 1, Severity: validate.SeverityError})
+			errorCount++
 		} else {
-			if t.Name() == "" {
-				issues = append(issues, "missing name/title field")
-			}
-			if t.Status() == "" {
-				issues = append(issues, "missing status field")
-			} else if !thread.IsValidStatus(t.Status()) {
-				issues = append(issues, fmt.Sprintf("invalid status '%s'", thread.BaseStatus(t.Status())))
+			for _, issue := range runner.Check(parsed[file]) {
+				issues = append(issues, fmt.Sprintf("[%s] %s", issue.RuleID, issue.Message))
+				line := issue.Line
+				if line == 0 {
+					line = 1
+				}
+				lineIssues = append(lineIssues, lineIssue{RuleID: issue.RuleID, Message: issue.Message, Line: line, Severity: issue.Severity})
+				if issue.Severity == validate.SeverityError {
+					errorCount++
+				}
 			}
 		}
 
-		valid := len(issues) == 0
-		if !valid {
-			errorCount++
-		}
-
 		results = append(results, validationResult{
-			Path:   relPath,
-			Valid:  valid,
-			Issues: issues,
+			Path:       relPath,
+			Valid:      len(issues) == 0,
+			Issues:     issues,
+			lineIssues: lineIssues,
 		})
 	}
 
+	switch renderFormat {
+	case "ci":
+		return renderValidateGitHubCI(results)
+	case "junit":
+		return renderValidateJUnit(results)
+	case "sarif":
+		return renderValidateSarif(results)
+	}
+
 	// Output based on format
 	switch fmt_ {
 	case output.FormatFancy, output.FormatPlain:
@@ -139,3 +248,282 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// renderValidateGitHubCI prints GitHub Actions workflow-command
+// annotations grouped per file: ::error for error-severity issues,
+// ::warning for warning/info ones, plus a $GITHUB_STEP_SUMMARY table when
+// that env var is set. Only error-severity issues fail the run.
+func renderValidateGitHubCI(results []validationResult) error {
+	errorCount := 0
+	for _, r := range results {
+		if len(r.lineIssues) == 0 {
+			continue
+		}
+		fmt.Printf("::group::%s\n", r.Path)
+		for _, li := range r.lineIssues {
+			workflowCmd := "warning"
+			if li.Severity == validate.SeverityError {
+				workflowCmd = "error"
+				errorCount++
+			}
+			fmt.Printf("::%s file=%s,line=%d::%s\n", workflowCmd, escapeWorkflowProperty(r.Path), li.Line, escapeWorkflowData(li.Message))
+		}
+		fmt.Println("::endgroup::")
+	}
+
+	if summaryPath := os.Getenv("GITHUB_STEP_SUMMARY"); summaryPath != "" {
+		if err := writeValidateStepSummary(summaryPath, results); err != nil {
+			return err
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d validation error(s)", errorCount)
+	}
+	return nil
+}
+
+// escapeWorkflowData percent-encodes a GitHub Actions workflow command's
+// data segment (the text after the final "::"), per GitHub's documented
+// escaping rules. A thread name or validation message is user-controlled
+// content; without this, a "%" is misread as the start of another escape
+// and a CR/LF would split one annotation into several workflow commands
+// GitHub parses as independent (and forgeable) of the one we meant to emit.
+func escapeWorkflowData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// escapeWorkflowProperty percent-encodes a GitHub Actions workflow
+// command's property value (e.g. the value of "file="), which needs the
+// same escaping as escapeWorkflowData plus ":" and "," since those
+// delimit properties within the command.
+func escapeWorkflowProperty(s string) string {
+	s = escapeWorkflowData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	s = strings.ReplaceAll(s, ",", "%2C")
+	return s
+}
+
+// writeValidateStepSummary appends a Markdown OK/WARN table to
+// $GITHUB_STEP_SUMMARY, which GitHub Actions renders on the job's summary
+// page.
+func writeValidateStepSummary(path string, results []validationResult) error {
+	var sb strings.Builder
+	sb.WriteString("## threads validate\n\n")
+	sb.WriteString("| File | Status | Issues |\n")
+	sb.WriteString("|---|---|---|\n")
+
+	okCount, warnCount := 0, 0
+	for _, r := range results {
+		status := "OK"
+		if r.Valid {
+			okCount++
+		} else {
+			status = "WARN"
+			warnCount++
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", r.Path, status, strings.Join(r.Issues, "; ")))
+	}
+	sb.WriteString(fmt.Sprintf("\n**%d OK, %d WARN**\n", okCount, warnCount))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("writing GITHUB_STEP_SUMMARY: %w", err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(sb.String())
+	return err
+}
+
+// junitTestsuite is the minimal JUnit XML shape GitLab's test-report
+// widget (and most CI JUnit consumers) expect: one testcase per thread
+// file, with a failure entry per validation issue.
+type junitTestsuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Testcases []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+	Name      string         `xml:"name,attr"`
+	Classname string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// renderValidateJUnit prints a JUnit XML <testsuite> document to stdout.
+// Every issue (any severity) becomes a <failure>, since JUnit has no
+// widely-supported notion of "warning"; only error-severity issues affect
+// the command's exit code.
+func renderValidateJUnit(results []validationResult) error {
+	suite := junitTestsuite{Name: "threads validate", Tests: len(results)}
+	errorCount := 0
+
+	for _, r := range results {
+		tc := junitTestcase{Name: r.Path, Classname: "threads.validate"}
+		for _, li := range r.lineIssues {
+			if li.Severity == validate.SeverityError {
+				errorCount++
+			}
+			tc.Failures = append(tc.Failures, junitFailure{
+				Message: li.Message,
+				Text:    fmt.Sprintf("%s:%d: %s", r.Path, li.Line, li.Message),
+			})
+		}
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Testcases = append(suite.Testcases, tc)
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("XML serialization failed: %v", err)
+	}
+	fmt.Println(xml.Header + string(out))
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d validation error(s)", errorCount)
+	}
+	return nil
+}
+
+// sarifLog is the minimal SARIF 2.1.0 shape GitHub's
+// github/codeql-action/upload-sarif accepts: one run, one tool driver with
+// the rule catalog, and one result per issue.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifText         `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// renderValidateSarif prints a SARIF 2.1.0 log to stdout, for uploading via
+// github/codeql-action/upload-sarif so thread-hygiene issues show up
+// alongside other linters in Security -> Code scanning alerts.
+func renderValidateSarif(results []validationResult) error {
+	var rules []sarifRule
+	for _, d := range validate.RuleDescriptions() {
+		rules = append(rules, sarifRule{ID: d.ID, ShortDescription: sarifText{Text: d.ShortDescription}})
+	}
+	rules = append(rules, sarifRule{ID: "parse-error", ShortDescription: sarifText{Text: "Thread file failed to parse"}})
+
+	var sarifResults []sarifResult
+	errorCount := 0
+	for _, r := range results {
+		for _, li := range r.lineIssues {
+			if li.Severity == validate.SeverityError {
+				errorCount++
+			}
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.Path}}
+			if li.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: li.Line}
+			}
+			sarifResults = append(sarifResults, sarifResult{
+				RuleID:    li.RuleID,
+				Level:     sarifLevel(li.Severity),
+				Message:   sarifText{Text: li.Message},
+				Locations: []sarifLocation{{PhysicalLocation: loc}},
+				PartialFingerprints: map[string]string{
+					"primaryLocationLineHash": sarifLineHash(li.RuleID, r.Path, li.Line),
+				},
+			})
+		}
+	}
+
+	doc := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "threads", Rules: rules}},
+			Results: sarifResults,
+		}},
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("SARIF serialization failed: %v", err)
+	}
+	fmt.Println(string(out))
+
+	if errorCount > 0 {
+		return fmt.Errorf("%d validation error(s)", errorCount)
+	}
+	return nil
+}
+
+// sarifLevel maps a rule's severity to one of SARIF's three result levels.
+func sarifLevel(sev validate.Severity) string {
+	switch sev {
+	case validate.SeverityError:
+		return "error"
+	case validate.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifLineHash derives a stable per-(rule, file, line) fingerprint, so
+// code-scanning can dedup the same issue across runs. The line number is
+// part of the hash, so an issue whose line moves gets a new fingerprint.
+func sarifLineHash(ruleID, path string, line int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", ruleID, path, line)))
+	return hex.EncodeToString(sum[:])
+}
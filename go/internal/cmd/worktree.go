@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+var (
+	worktreeBaseRef string
+	worktreeScript  string
+	worktreeMsg     string
+	worktreeDiscard bool
+)
+
+var worktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Run a batch of thread commands in an isolated git worktree",
+	Long: `Apply a sequence of thread mutations (new, update, status, body,
+reopen, ...) inside a disposable git worktree checked out from --base-ref,
+so the user's working copy is never touched while the batch runs.
+
+The batch is a YAML or JSON list of command lines, one per step, e.g.:
+
+  - new "Fix flaky test" --desc "investigate CI"
+  - status abc123 resolved
+
+Read from --script, or stdin if --script is omitted. If every step
+succeeds, the worktree's changes are committed and cherry-picked onto the
+current branch; on any failure, or with --discard, the worktree is removed
+without merging anything back.`,
+	RunE: runWorktree,
+}
+
+func init() {
+	worktreeCmd.Flags().StringVar(&worktreeBaseRef, "base-ref", "HEAD", "Ref the isolated worktree is checked out from")
+	worktreeCmd.Flags().StringVar(&worktreeScript, "script", "", "Batch file to read (default: stdin)")
+	worktreeCmd.Flags().StringVarP(&worktreeMsg, "m", "m", "", "Commit message for the merged-back commit")
+	worktreeCmd.Flags().BoolVar(&worktreeDiscard, "discard", false, "Discard the worktree's changes instead of merging them back")
+}
+
+func runWorktree(cmd *cobra.Command, args []string) error {
+	originalWs := getWorkspace()
+
+	var src io.Reader = os.Stdin
+	if worktreeScript != "" {
+		f, err := os.Open(worktreeScript)
+		if err != nil {
+			return fmt.Errorf("opening --script: %w", err)
+		}
+		defer f.Close()
+		src = f
+	}
+
+	steps, err := parseBatch(src)
+	if err != nil {
+		return fmt.Errorf("parsing batch: %w", err)
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf("batch is empty")
+	}
+
+	wt, err := git.NewWorktreeFromRef(originalWs, worktreeBaseRef)
+	if err != nil {
+		return err
+	}
+	defer wt.Close()
+
+	// Run every step against the worktree instead of the user's workspace.
+	// ws is the package-level var getWorkspace() returns.
+	ws = wt.Path()
+	defer func() { ws = originalWs }()
+
+	for i, step := range steps {
+		if err := runBatchStep(step); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, strings.Join(step, " "), err)
+		}
+	}
+
+	if worktreeDiscard {
+		fmt.Println("Batch succeeded; discarding worktree as requested.")
+		return nil
+	}
+
+	return mergeWorktreeBack(wt, originalWs)
+}
+
+// runBatchStep dispatches one step (e.g. ["new", "Fix bug"]) to the
+// matching top-level subcommand, the same way the CLI itself would.
+func runBatchStep(step []string) error {
+	if len(step) == 0 {
+		return nil
+	}
+	target, remaining, err := rootCmd.Find(step)
+	if err != nil {
+		return err
+	}
+	if target == rootCmd {
+		return fmt.Errorf("unknown command %q", step[0])
+	}
+	// Subcommands are singletons reused across steps; reset their flags to
+	// defaults first so one step's flags can't leak into the next.
+	target.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+	if err := target.ParseFlags(remaining); err != nil {
+		return err
+	}
+	if target.RunE == nil {
+		return fmt.Errorf("command %q cannot run in a batch", step[0])
+	}
+	positional := target.Flags().Args()
+	if target.Args != nil {
+		if err := target.Args(target, positional); err != nil {
+			return err
+		}
+	}
+	return target.RunE(target, positional)
+}
+
+// mergeWorktreeBack commits every changed thread file inside wt and
+// cherry-picks the result onto originalWs's current branch, mirroring
+// commit.go's commitViaWorktree.
+func mergeWorktreeBack(wt *git.Worktree, originalWs string) error {
+	threads, err := workspace.FindAllThreads(wt.Path())
+	if err != nil {
+		return err
+	}
+
+	var relPaths []string
+	for _, t := range threads {
+		relPath, _ := filepath.Rel(wt.Path(), t)
+		if git.HasChanges(wt.Path(), relPath) {
+			relPaths = append(relPaths, relPath)
+		}
+	}
+	if len(relPaths) == 0 {
+		fmt.Println("Batch made no thread changes; nothing to merge.")
+		return nil
+	}
+
+	msg := worktreeMsg
+	if msg == "" {
+		msg = git.GenerateCommitMessage(wt.Path(), threads)
+	}
+
+	if err := git.Commit(wt.Path(), relPaths, msg); err != nil {
+		return err
+	}
+
+	hash, err := git.HeadCommit(wt.Path())
+	if err != nil {
+		return fmt.Errorf("resolving worktree commit: %w", err)
+	}
+
+	if err := git.MergeBack(originalWs, hash); err != nil {
+		return fmt.Errorf("merging worktree commit back: %w", err)
+	}
+
+	fmt.Printf("Committed %d thread(s) in worktree and merged back.\n", len(relPaths))
+	return nil
+}
+
+// parseBatch reads a YAML or JSON list of command lines (YAML is a JSON
+// superset, so one decoder handles both) and splits each into argv with
+// shellSplit.
+func parseBatch(r io.Reader) ([][]string, error) {
+	var lines []string
+	dec := yaml.NewDecoder(bufio.NewReader(r))
+	if err := dec.Decode(&lines); err != nil {
+		return nil, err
+	}
+
+	steps := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		argv, err := shellSplit(line)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", line, err)
+		}
+		if len(argv) > 0 {
+			steps = append(steps, argv)
+		}
+	}
+	return steps, nil
+}
+
+// shellSplit splits a command line into argv, honoring single and double
+// quotes (no escape sequences) so thread titles/descriptions containing
+// spaces can be passed as one argument.
+func shellSplit(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var quote rune
+	inArg := false
+
+	flush := func() {
+		if inArg {
+			args = append(args, cur.String())
+			cur.Reset()
+			inArg = false
+		}
+	}
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			flush()
+		default:
+			inArg = true
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return args, nil
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gossh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// remoteAuth resolves credentials for ws's "origin" remote, for Push.
+func remoteAuth(repo *git.Repository) (transport.AuthMethod, error) {
+	return authForRemote(repo, "origin")
+}
+
+// authForRemote resolves credentials for remoteName, for FetchRefspec and
+// PushRefspec.
+func authForRemote(repo *git.Repository, remoteName string) (transport.AuthMethod, error) {
+	remote, err := repo.Remote(remoteName)
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return nil, nil
+	}
+	return resolveAuth(remote.Config().URLs[0])
+}
+
+// resolveAuth picks a credential source for remoteURL: an ssh-agent for SSH
+// remotes, or a matching entry from ~/.git-credentials (the plaintext store
+// git's own "store" credential helper writes) for HTTPS ones. A nil
+// AuthMethod with a nil error means "no credentials needed or found" - the
+// caller then tries the request without auth, same as a plain `git push`
+// would against a public remote.
+//
+// This doesn't reach into an OS keychain (macOS Keychain, Windows Credential
+// Manager, libsecret) - those need platform-specific bindings this package
+// doesn't pull in. ssh-agent and the credential "store" file cover the
+// common case; anything beyond that still needs -tags cliexec.
+func resolveAuth(remoteURL string) (transport.AuthMethod, error) {
+	switch {
+	case strings.HasPrefix(remoteURL, "ssh://"), isScpLike(remoteURL):
+		auth, err := gossh.NewSSHAgentAuth(sshUser(remoteURL))
+		if err != nil {
+			// No agent running is routine (e.g. CI without one) and not
+			// fatal: leave auth nil and let the transport fall back to
+			// whatever its own defaults allow.
+			return nil, nil
+		}
+		return auth, nil
+	case strings.HasPrefix(remoteURL, "https://"), strings.HasPrefix(remoteURL, "http://"):
+		return credentialsFromStore(remoteURL)
+	default:
+		return nil, nil
+	}
+}
+
+// isScpLike reports whether remoteURL is an scp-style SSH remote, e.g.
+// "git@example.com:org/repo.git".
+func isScpLike(remoteURL string) bool {
+	return strings.Contains(remoteURL, "@") && strings.Contains(remoteURL, ":") && !strings.Contains(remoteURL, "://")
+}
+
+func sshUser(remoteURL string) string {
+	u := strings.TrimPrefix(remoteURL, "ssh://")
+	if i := strings.Index(u, "@"); i > 0 {
+		return u[:i]
+	}
+	return "git"
+}
+
+// credentialsFromStore looks up remoteURL's host in ~/.git-credentials.
+func credentialsFromStore(remoteURL string) (transport.AuthMethod, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil
+	}
+	f, err := os.Open(filepath.Join(home, ".git-credentials"))
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	target, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		stored, err := url.Parse(strings.TrimSpace(scanner.Text()))
+		if err != nil || stored.Host == "" || stored.Host != target.Host {
+			continue
+		}
+		password, _ := stored.User.Password()
+		return &http.BasicAuth{Username: stored.User.Username(), Password: password}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,21 @@
+//go:build cliexec
+
+package git
+
+import (
+	"fmt"
+	"testing"
+)
+
+// BenchmarkHasChanges_CLI is BenchmarkHasChanges_GoGit's CLI counterpart;
+// see backend_bench_test.go.
+func BenchmarkHasChanges_CLI(b *testing.B) {
+	dir := setupBenchRepo(b, 200)
+	client := NewCLIClient(dir)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 200; j++ {
+			client.HasChanges(fmt.Sprintf(".threads/thread-%d.md", j))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// setupBenchRepo creates a throwaway repo with n tracked, unmodified thread
+// files, used to compare backends on a realistic HasChanges scan.
+func setupBenchRepo(tb testing.TB, n int) string {
+	tb.Helper()
+	dir := tb.TempDir()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			tb.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "bench@example.com")
+	run("config", "user.name", "bench")
+
+	threadsDir := filepath.Join(dir, ".threads")
+	if err := os.MkdirAll(threadsDir, 0755); err != nil {
+		tb.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		path := filepath.Join(threadsDir, fmt.Sprintf("thread-%d.md", i))
+		if err := os.WriteFile(path, []byte("# thread\n"), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "seed")
+
+	return dir
+}
+
+// BenchmarkHasChanges_GoGit and (behind -tags cliexec) BenchmarkHasChanges_CLI
+// scan the same repository via each backend, demonstrating the cost of
+// reusing one cached go-git *Repository versus forking `git` once per file.
+func BenchmarkHasChanges_GoGit(b *testing.B) {
+	dir := setupBenchRepo(b, 200)
+	client := NewGoGitClient(dir)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 200; j++ {
+			client.HasChanges(fmt.Sprintf(".threads/thread-%d.md", j))
+		}
+	}
+}
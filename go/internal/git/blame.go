@@ -0,0 +1,17 @@
+package git
+
+// BlameLine is one line of a file's current content, annotated with the
+// commit that last touched it.
+type BlameLine struct {
+	Line   int // 1-based line number in the current file
+	Commit string
+	Author string
+	Date   string // RFC3339, from the commit's author time
+}
+
+// Blame returns one BlameLine per line of relPath's current content, the
+// basis for mapping thread items (Notes, Todo, Log entries) back to the
+// commit that last changed them.
+func Blame(ws, relPath string) ([]BlameLine, error) {
+	return defaultRunner.Blame(ws, relPath)
+}
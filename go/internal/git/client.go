@@ -0,0 +1,88 @@
+package git
+
+// Client is a workspace-scoped handle over a single Runner backend, for
+// callers that want to bind a workspace once instead of passing ws to every
+// package-level helper (HasChanges, Commit, ...). The free functions built
+// on defaultRunner remain the primary API; Client exists for call sites
+// that explicitly want one backend regardless of the global THREADS_GIT_BACKEND
+// selection, e.g. comparing the two in a benchmark.
+type Client struct {
+	ws     string
+	runner Runner
+}
+
+// NewGoGitClient returns a Client bound to ws that always uses the
+// embedded pure-Go backend, regardless of the globally selected backend.
+func NewGoGitClient(ws string) *Client {
+	return &Client{ws: ws, runner: goGitRunner{}}
+}
+
+func (c *Client) HasChanges(relPath string) bool {
+	return c.runner.HasChanges(c.ws, relPath)
+}
+
+func (c *Client) IsTracked(relPath string) bool {
+	return c.runner.IsTracked(c.ws, relPath)
+}
+
+func (c *Client) ExistsInHEAD(relPath string) bool {
+	return c.runner.ExistsInHEAD(c.ws, relPath)
+}
+
+func (c *Client) Add(files ...string) error {
+	return c.runner.Add(c.ws, files...)
+}
+
+func (c *Client) Commit(files []string, message string) error {
+	return c.runner.Commit(c.ws, files, message)
+}
+
+// CommitOptions customizes a commit beyond a plain message, for callers
+// (Transaction.Commit behind `threads batch`, `threads git commit --sign`)
+// that want to record a specific
+// author regardless of whoever's git config is active, or GPG-sign the
+// result.
+type CommitOptions struct {
+	Message string
+	Author  string // "Name <email>"; empty keeps the repository's configured user, as Commit does
+	SignKey string // path to an armored PGP private key; empty skips signing
+}
+
+// CommitWithOptions commits files with opts.Message, overriding the author
+// if opts.Author is set and GPG-signing with opts.SignKey if set. Only the
+// go-git backend supports either override; against any other Runner,
+// opts.Author and opts.SignKey are ignored and this behaves like Commit.
+func (c *Client) CommitWithOptions(files []string, opts CommitOptions) error {
+	gg, ok := c.runner.(goGitRunner)
+	if !ok || (opts.Author == "" && opts.SignKey == "") {
+		return c.Commit(files, opts.Message)
+	}
+	return gg.commitWithOptions(c.ws, files, opts)
+}
+
+func (c *Client) FindDeletedThreadFiles() ([]string, error) {
+	return c.runner.FindDeletedThreadFiles(c.ws)
+}
+
+func (c *Client) Push() error {
+	return c.runner.Push(c.ws)
+}
+
+// Repo is a Client bound to the go-git backend whose repository handle has
+// already been opened, for commands (such as `threads git commit`) that
+// want to fail fast on a bad workspace before doing any work, rather
+// than discovering it on their first Add or Commit call.
+type Repo struct {
+	*Client
+}
+
+// Open opens ws's repository once via the go-git backend and returns a
+// Repo wrapping it. Since goGitRunner already caches one *git.Repository
+// per workspace, every other Repo or package-level call against the same
+// ws reuses this same handle rather than reopening it.
+func Open(ws string) (*Repo, error) {
+	if _, err := (goGitRunner{}).open(ws); err != nil {
+		return nil, err
+	}
+	return &Repo{Client: NewGoGitClient(ws)}, nil
+}
@@ -0,0 +1,10 @@
+//go:build cliexec
+
+package git
+
+// NewCLIClient returns a Client bound to ws that always shells out to the
+// git binary, regardless of the globally selected backend. Only available
+// in binaries built with -tags cliexec.
+func NewCLIClient(ws string) *Client {
+	return &Client{ws: ws, runner: execRunner{}}
+}
@@ -0,0 +1,274 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogEntry describes one git invocation, passed to LogFn after it
+// completes. `threads git --verbose` and similar tracing surfaces use this
+// to show exact argv, duration, and outcome.
+type LogEntry struct {
+	Argv     []string
+	Dir      string
+	Duration time.Duration
+	Err      error
+}
+
+// LogFn, when non-nil, is called once per Cmd invocation. Callers that want
+// to trace git activity (e.g. a --verbose flag) set this at startup.
+var LogFn func(LogEntry)
+
+// defaultCtx is used by any Cmd that doesn't call WithContext explicitly.
+// SetContext lets PersistentPreRunE wire up a cancellable/timeout-bound
+// context once, without every call site needing to pass one through.
+var defaultCtx = context.Background()
+
+// SetContext sets the context new Cmds use by default.
+func SetContext(ctx context.Context) {
+	defaultCtx = ctx
+}
+
+// Cmd builds a git invocation while keeping flags and user-derived values
+// (file paths, refs, commit messages assembled from thread filenames)
+// clearly separated. AddOption is for literal, developer-written flags;
+// AddValue is for values that come from the outside world and must not be
+// interpretable as options. A thread filename moved via `threads move`
+// (e.g. "abc123---upload-pack.md") must never be read as "--upload-pack".
+type Cmd struct {
+	ws      string
+	sub     string
+	option  []string
+	dashed  bool
+	dashAt  int // index into value where the "--" separator goes
+	value   []string
+	err     error
+	ctx     context.Context
+	env     []string
+	stdin   io.Reader
+	timeout time.Duration
+}
+
+// NewCmd starts building a git invocation for the given subcommand
+// (e.g. "add", "commit", "diff") against workspace ws.
+func NewCmd(ws, subcommand string) *Cmd {
+	return &Cmd{ws: ws, sub: subcommand}
+}
+
+// AddOption appends one or more literal flags (e.g. "--quiet", "-m"). These
+// are developer-controlled constants, never user input, so no validation
+// is performed.
+func (c *Cmd) AddOption(opts ...string) *Cmd {
+	c.option = append(c.option, opts...)
+	return c
+}
+
+// safeArgRe is the whitelist a SafeArg must satisfy.
+var safeArgRe = regexp.MustCompile(`^[a-zA-Z0-9=_.-]+$`)
+
+// SafeArg is a flag literal that has been checked against safeArgRe. It
+// exists so AddArguments can accept a compile-time-reviewable type instead
+// of a bare string, for call sites that build flags from small pieces
+// (e.g. "--depth=" + n) rather than writing them as constants.
+type SafeArg string
+
+// NewSafeArg validates s against safeArgRe, returning an error if it
+// contains anything that isn't a flag character.
+func NewSafeArg(s string) (SafeArg, error) {
+	if !safeArgRe.MatchString(s) {
+		return "", fmt.Errorf("git: %q is not a safe literal argument", s)
+	}
+	return SafeArg(s), nil
+}
+
+// AddArguments appends literal flags built from SafeArg values, a
+// type-checked alternative to AddOption for call sites that assemble a
+// flag rather than writing it as a constant.
+func (c *Cmd) AddArguments(args ...SafeArg) *Cmd {
+	for _, a := range args {
+		c.option = append(c.option, string(a))
+	}
+	return c
+}
+
+// AddDynamicArguments is an alias for AddValue, named to match how other
+// git wrappers in this codebase's ecosystem describe user-derived,
+// dash-rejecting arguments.
+func (c *Cmd) AddDynamicArguments(values ...string) *Cmd {
+	return c.AddValue(values...)
+}
+
+// AddDashesAndList is an alias for AddDashedValue.
+func (c *Cmd) AddDashesAndList(values ...string) *Cmd {
+	return c.AddDashedValue(values...)
+}
+
+// AddValue appends one or more user-derived values (paths, refs, messages).
+// Any value beginning with "-" is rejected: such a value, if passed to
+// git unguarded, could be misread as an option.
+func (c *Cmd) AddValue(values ...string) *Cmd {
+	if c.err != nil {
+		return c
+	}
+	for _, v := range values {
+		if strings.HasPrefix(v, "-") {
+			c.err = fmt.Errorf("git: refusing to pass %q as a bare argument (looks like an option); use AddDashedValue or AddOption", v)
+			return c
+		}
+	}
+	c.value = append(c.value, values...)
+	return c
+}
+
+// AddDashedValue appends user-derived values preceded by a literal "--"
+// separator, so git treats everything after it as positional (paths/refs)
+// no matter what it looks like. Use this for ref/path arguments that may
+// legitimately start with "-".
+func (c *Cmd) AddDashedValue(values ...string) *Cmd {
+	if !c.dashed {
+		c.dashed = true
+		c.dashAt = len(c.value)
+	}
+	c.value = append(c.value, values...)
+	return c
+}
+
+// WithContext attaches a context to the invocation, overriding the
+// package's default (see SetContext). Cancelling it kills the subprocess.
+func (c *Cmd) WithContext(ctx context.Context) *Cmd {
+	c.ctx = ctx
+	return c
+}
+
+// WithTimeout bounds the invocation's runtime. Exceeding it cancels the
+// subprocess and the call fails with the context's deadline-exceeded error.
+func (c *Cmd) WithTimeout(d time.Duration) *Cmd {
+	c.timeout = d
+	return c
+}
+
+// WithEnv appends "KEY=VALUE" entries to the invocation's environment, on
+// top of the current process's environment.
+func (c *Cmd) WithEnv(env ...string) *Cmd {
+	c.env = append(c.env, env...)
+	return c
+}
+
+// WithStdin attaches a reader to feed the invocation's standard input.
+func (c *Cmd) WithStdin(r io.Reader) *Cmd {
+	c.stdin = r
+	return c
+}
+
+// RunOpts bundles the per-invocation overrides also settable individually
+// via WithContext/WithEnv/WithStdin/WithTimeout, for callers that build them
+// together (e.g. forwarding a caller-supplied RunOpts unchanged).
+type RunOpts struct {
+	Dir     string
+	Stdin   io.Reader
+	Env     []string
+	Timeout time.Duration
+}
+
+// WithOpts applies a RunOpts in one call. A zero Dir leaves the Cmd's
+// workspace unchanged.
+func (c *Cmd) WithOpts(opts *RunOpts) *Cmd {
+	if opts == nil {
+		return c
+	}
+	if opts.Dir != "" {
+		c.ws = opts.Dir
+	}
+	if opts.Stdin != nil {
+		c.stdin = opts.Stdin
+	}
+	if len(opts.Env) > 0 {
+		c.env = append(c.env, opts.Env...)
+	}
+	if opts.Timeout > 0 {
+		c.timeout = opts.Timeout
+	}
+	return c
+}
+
+// args assembles the final argv, or returns the first validation error
+// encountered while building.
+func (c *Cmd) args() ([]string, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	args := []string{"-C", c.ws, c.sub}
+	args = append(args, c.option...)
+	args = append(args, c.value[:c.dashAt]...)
+	if c.dashed {
+		args = append(args, "--")
+	}
+	args = append(args, c.value[c.dashAt:]...)
+	return args, nil
+}
+
+// run executes the built command, returning stdout and stderr separately
+// and reporting the invocation to LogFn if set.
+func (c *Cmd) run() (stdout, stderr string, err error) {
+	args, buildErr := c.args()
+	if buildErr != nil {
+		return "", "", buildErr
+	}
+
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = defaultCtx
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	stdout, stderr, runErr := shellRunnerImpl.Run(ctx, args, RunOpts{Env: c.env, Stdin: c.stdin})
+	duration := time.Since(start)
+
+	if LogFn != nil {
+		LogFn(LogEntry{Argv: args, Dir: c.ws, Duration: duration, Err: runErr})
+	}
+
+	if runErr != nil {
+		return stdout, stderr, fmt.Errorf("git %s failed: %s", c.sub, strings.TrimSpace(stderr))
+	}
+	return stdout, stderr, nil
+}
+
+// RunStdString executes the command and returns stdout and stderr
+// separately, for callers that need to tell diagnostic output apart from
+// the real result (e.g. a future `threads blame`/`threads log`).
+func (c *Cmd) RunStdString() (stdout string, stderr string, err error) {
+	return c.run()
+}
+
+// Run executes the command and returns stdout. On failure the error wraps
+// stderr, never swallowing it.
+func (c *Cmd) Run() (string, error) {
+	stdout, _, err := c.run()
+	if err != nil {
+		return "", err
+	}
+	return stdout, nil
+}
+
+// Output executes the command and returns stdout only, for callers that
+// need to parse the result (e.g. `git show :2:path`).
+func (c *Cmd) Output() (string, error) {
+	return c.Run()
+}
+
+// Success runs the command and reports only whether it succeeded,
+// for boolean checks like `git diff --quiet`.
+func (c *Cmd) Success() bool {
+	_, _, err := c.run()
+	return err == nil
+}
@@ -0,0 +1,78 @@
+package git
+
+import "testing"
+
+func TestAddValueRejectsDashPrefixed(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"main", false},
+		{"feature/x", false},
+		{"--upload-pack=evil", true},
+		{"-f", true},
+	}
+
+	for _, tt := range tests {
+		c := NewCmd("/repo", "checkout").AddValue(tt.value)
+		_, err := c.args()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("AddValue(%q): err = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAddDashedValueAllowsDashPrefixed(t *testing.T) {
+	c := NewCmd("/repo", "add").AddDashedValue("--upload-pack=evil", "-f.md")
+	args, err := c.args()
+	if err != nil {
+		t.Fatalf("args() = %v, want no error", err)
+	}
+	want := []string{"-C", "/repo", "add", "--", "--upload-pack=evil", "-f.md"}
+	if !equalArgs(args, want) {
+		t.Errorf("args() = %v, want %v", args, want)
+	}
+}
+
+func TestNewSafeArg(t *testing.T) {
+	tests := []struct {
+		arg     string
+		wantErr bool
+	}{
+		{"--depth=5", false},
+		{"--quiet", false},
+		{"$(rm -rf /)", true},
+		{"; rm -rf /", true},
+	}
+
+	for _, tt := range tests {
+		_, err := NewSafeArg(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewSafeArg(%q): err = %v, wantErr %v", tt.arg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCommitMessageIsSingleToken(t *testing.T) {
+	c := NewCmd("/repo", "commit").AddOption("--message=" + "-f; rm -rf /").AddDashedValue("thread.md")
+	args, err := c.args()
+	if err != nil {
+		t.Fatalf("args() = %v, want no error", err)
+	}
+	want := []string{"-C", "/repo", "commit", "--message=-f; rm -rf /", "--", "thread.md"}
+	if !equalArgs(args, want) {
+		t.Errorf("args() = %v, want %v", args, want)
+	}
+}
+
+func equalArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
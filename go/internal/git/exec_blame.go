@@ -0,0 +1,58 @@
+//go:build cliexec
+
+package git
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blame implements Runner by shelling out to `git blame --line-porcelain`.
+func (execRunner) Blame(ws, relPath string) ([]BlameLine, error) {
+	out, err := NewCmd(ws, "blame").AddOption("--line-porcelain").AddDashedValue(relPath).Run()
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(out), nil
+}
+
+// parseBlamePorcelain parses `git blame --line-porcelain` output, which
+// repeats each commit's metadata (author, author-time, ...) for every line;
+// it's recorded by commit hash as it's encountered.
+func parseBlamePorcelain(out string) []BlameLine {
+	var lines []BlameLine
+	authors := map[string]string{}
+	times := map[string]string{}
+
+	var commit string
+	var finalLine int
+
+	for _, raw := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(raw, "\t"):
+			lines = append(lines, BlameLine{
+				Line:   finalLine,
+				Commit: commit,
+				Author: authors[commit],
+				Date:   times[commit],
+			})
+
+		case strings.HasPrefix(raw, "author "):
+			authors[commit] = strings.TrimPrefix(raw, "author ")
+
+		case strings.HasPrefix(raw, "author-time "):
+			ts, _ := strconv.ParseInt(strings.TrimPrefix(raw, "author-time "), 10, 64)
+			times[commit] = time.Unix(ts, 0).UTC().Format(time.RFC3339)
+
+		default:
+			fields := strings.Fields(raw)
+			if len(fields) >= 3 && len(fields[0]) == 40 && isHex(fields[0]) {
+				commit = fields[0]
+				finalLine, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,204 @@
+//go:build cliexec
+
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// execRunner implements Runner by shelling out to the git binary. It's the
+// only backend that works with git hooks, credential helpers, and signed
+// commits configured in the user's real git install, and is opt-in: build
+// with -tags cliexec to include it, since the default goGitRunner backend
+// needs no git binary and is faster for workspaces with many threads.
+// Every invocation goes through Cmd so user-derived paths/refs/messages
+// can never be misread as options.
+type execRunner struct{}
+
+// newExecRunner returns the subprocess backend. Compiled in only when
+// built with -tags cliexec; see exec_runner_disabled.go for the fallback.
+func newExecRunner() (Runner, error) {
+	return execRunner{}, nil
+}
+
+// DiscoverRoot finds the repository root containing startPath.
+func (execRunner) DiscoverRoot(startPath string) (string, error) {
+	out, err := NewCmd(startPath, "rev-parse").AddOption("--show-toplevel").Run()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository at %s: %w", startPath, err)
+	}
+	root := strings.TrimSpace(out)
+	if root == "" {
+		return "", fmt.Errorf("git root is empty")
+	}
+	return root, nil
+}
+
+// HasChanges checks if a file has uncommitted changes (staged, unstaged, or untracked)
+func (r execRunner) HasChanges(ws, relPath string) bool {
+	// Check unstaged changes
+	if !NewCmd(ws, "diff").AddOption("--quiet").AddDashedValue(relPath).Success() {
+		return true
+	}
+
+	// Check staged changes
+	if !NewCmd(ws, "diff").AddOption("--cached", "--quiet").AddDashedValue(relPath).Success() {
+		return true
+	}
+
+	// Check if untracked
+	if !r.IsTracked(ws, relPath) {
+		return true
+	}
+
+	return false
+}
+
+// IsTracked checks if a file is tracked by git
+func (execRunner) IsTracked(ws, relPath string) bool {
+	return NewCmd(ws, "ls-files").AddOption("--error-unmatch").AddDashedValue(relPath).Success()
+}
+
+// ExistsInHEAD checks if a file exists in HEAD
+func (execRunner) ExistsInHEAD(ws, relPath string) bool {
+	ref := "HEAD:" + relPath
+	return NewCmd(ws, "cat-file").AddOption("-e").AddDashedValue(ref).Success()
+}
+
+// Add stages files, skipping any that don't exist (assumed to be already-staged deletions)
+func (execRunner) Add(ws string, files ...string) error {
+	var existingFiles []string
+	for _, f := range files {
+		fullPath := f
+		if !filepath.IsAbs(f) {
+			fullPath = filepath.Join(ws, f)
+		}
+		if fileExists(fullPath) {
+			existingFiles = append(existingFiles, f)
+		}
+		// Non-existent files are assumed to be deletions already staged
+	}
+
+	if len(existingFiles) == 0 {
+		return nil
+	}
+
+	_, err := NewCmd(ws, "add").AddDashedValue(existingFiles...).Run()
+	return err
+}
+
+// Commit creates a commit with the given message
+func (r execRunner) Commit(ws string, files []string, message string) error {
+	// Stage files
+	if err := r.Add(ws, files...); err != nil {
+		return err
+	}
+
+	// Commit. The message is folded into a single "--message=..." token so
+	// its contents, even a leading "-", can never be re-parsed as a
+	// separate option the way a bare "-m", message pair could be.
+	_, err := NewCmd(ws, "commit").AddOption("--message=" + message).AddDashedValue(files...).Run()
+	return err
+}
+
+// Push does git pull --rebase && git push
+func (execRunner) Push(ws string) error {
+	// Pull with rebase
+	if _, err := NewCmd(ws, "pull").AddOption("--rebase").Run(); err != nil {
+		return err
+	}
+
+	// Push
+	if _, err := NewCmd(ws, "push").Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindDeletedThreadFiles returns paths of deleted thread files that are staged or in working tree
+// These are files matching .threads/*.md that show as deleted (D) in git status
+func (execRunner) FindDeletedThreadFiles(ws string) ([]string, error) {
+	output, err := NewCmd(ws, "status").AddOption("--porcelain").Run()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if len(line) < 4 {
+			continue
+		}
+		// Porcelain format: XY PATH
+		// X = index status, Y = worktree status
+		// D in either position means deleted
+		indexStatus := line[0]
+		worktreeStatus := line[1]
+		path := line[3:]
+
+		// Check if it's a deletion and matches thread file pattern
+		if (indexStatus == 'D' || worktreeStatus == 'D') && isThreadPath(path) {
+			deleted = append(deleted, filepath.Join(ws, path))
+		}
+	}
+
+	return deleted, nil
+}
+
+// FetchRefspec runs `git fetch <remote> <refspec>`.
+func (execRunner) FetchRefspec(ws, remote, refspec string) error {
+	_, err := NewCmd(ws, "fetch").AddDashedValue(remote, refspec).Run()
+	return err
+}
+
+// PushRefspec runs `git push <remote> <refspec>`.
+func (execRunner) PushRefspec(ws, remote, refspec string) error {
+	_, err := NewCmd(ws, "push").AddDashedValue(remote, refspec).Run()
+	return err
+}
+
+// ConfigGet runs `git config --get <section>.<key>`.
+func (execRunner) ConfigGet(ws, section, key string) (string, bool) {
+	out, err := NewCmd(ws, "config").AddOption("--get").AddValue(section + "." + key).Run()
+	if err != nil {
+		return "", false
+	}
+	value := strings.TrimSpace(out)
+	if value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// ConfigSet runs `git config <section>.<key> <value>`.
+func (execRunner) ConfigSet(ws, section, key, value string) error {
+	_, err := NewCmd(ws, "config").AddValue(section+"."+key, value).Run()
+	return err
+}
+
+// ResolveCommit runs `git rev-parse <rev>`.
+func (execRunner) ResolveCommit(ws, rev string) (string, error) {
+	out, err := NewCmd(ws, "rev-parse").AddValue(rev).Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// ChangedThreadFiles runs `git diff --name-only <fromRev> <toRev> -- .threads`.
+func (execRunner) ChangedThreadFiles(ws, fromRev, toRev string) ([]string, error) {
+	out, err := NewCmd(ws, "diff").AddOption("--name-only").AddValue(fromRev, toRev).AddDashedValue(".threads").Run()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,14 @@
+//go:build !cliexec
+
+package git
+
+import "fmt"
+
+// newExecRunner reports that the subprocess backend wasn't compiled in.
+// Build with -tags cliexec to include exec_runner.go's real
+// implementation (for git hooks or credential helpers that only work
+// through the actual git binary); otherwise threads always uses the
+// embedded goGitRunner backend.
+func newExecRunner() (Runner, error) {
+	return nil, fmt.Errorf("the exec git backend isn't available in this build; rebuild with -tags cliexec")
+}
@@ -1,108 +1,67 @@
+// Package git wraps the git operations threads needs (staging, committing,
+// pushing, change detection) behind a small Runner interface so the
+// implementation can be swapped between shelling out to the git binary and
+// an embedded pure-Go backend. See runner.go for backend selection.
 package git
 
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
 
+// DiscoverRoot finds the git repository root containing startPath.
+func DiscoverRoot(startPath string) (string, error) {
+	return defaultRunner.DiscoverRoot(startPath)
+}
+
 // HasChanges checks if a file has uncommitted changes (staged, unstaged, or untracked)
 func HasChanges(ws, relPath string) bool {
-	// Check unstaged changes
-	cmd := exec.Command("git", "-C", ws, "diff", "--quiet", "--", relPath)
-	if err := cmd.Run(); err != nil {
-		return true
-	}
-
-	// Check staged changes
-	cmd = exec.Command("git", "-C", ws, "diff", "--cached", "--quiet", "--", relPath)
-	if err := cmd.Run(); err != nil {
-		return true
-	}
-
-	// Check if untracked
-	if !IsTracked(ws, relPath) {
-		return true
-	}
-
-	return false
+	return defaultRunner.HasChanges(ws, relPath)
 }
 
 // IsTracked checks if a file is tracked by git
 func IsTracked(ws, relPath string) bool {
-	cmd := exec.Command("git", "-C", ws, "ls-files", "--error-unmatch", relPath)
-	return cmd.Run() == nil
+	return defaultRunner.IsTracked(ws, relPath)
 }
 
 // ExistsInHEAD checks if a file exists in HEAD
 func ExistsInHEAD(ws, relPath string) bool {
-	ref := "HEAD:" + relPath
-	cmd := exec.Command("git", "-C", ws, "cat-file", "-e", ref)
-	return cmd.Run() == nil
+	return defaultRunner.ExistsInHEAD(ws, relPath)
 }
 
 // Add stages files, skipping any that don't exist (assumed to be already-staged deletions)
 func Add(ws string, files ...string) error {
-	var existingFiles []string
-	for _, f := range files {
-		fullPath := f
-		if !filepath.IsAbs(f) {
-			fullPath = filepath.Join(ws, f)
-		}
-		if fileExists(fullPath) {
-			existingFiles = append(existingFiles, f)
-		}
-		// Non-existent files are assumed to be deletions already staged
-	}
-
-	if len(existingFiles) == 0 {
-		return nil
-	}
-
-	args := append([]string{"-C", ws, "add"}, existingFiles...)
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git add failed: %s", string(output))
-	}
-	return nil
+	return defaultRunner.Add(ws, files...)
 }
 
 // Commit creates a commit with the given message
 func Commit(ws string, files []string, message string) error {
-	// Stage files
-	if err := Add(ws, files...); err != nil {
-		return err
-	}
-
-	// Commit
-	args := []string{"-C", ws, "commit", "-m", message}
-	args = append(args, files...)
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git commit failed: %s", string(output))
-	}
-	return nil
+	return defaultRunner.Commit(ws, files, message)
 }
 
 // Push does git pull --rebase && git push
 func Push(ws string) error {
-	// Pull with rebase
-	cmd := exec.Command("git", "-C", ws, "pull", "--rebase")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git pull --rebase failed: %s", string(output))
-	}
+	return defaultRunner.Push(ws)
+}
 
-	// Push
-	cmd = exec.Command("git", "-C", ws, "push")
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git push failed: %s", string(output))
-	}
+// FindDeletedThreadFiles returns paths of deleted thread files that are staged or in working tree
+// These are files matching .threads/*.md that show as deleted (D) in git status
+func FindDeletedThreadFiles(ws string) ([]string, error) {
+	return defaultRunner.FindDeletedThreadFiles(ws)
+}
+
+// ResolveCommit resolves rev (HEAD, FETCH_HEAD, a branch, tag, or hash) to
+// its full commit hash.
+func ResolveCommit(ws, rev string) (string, error) {
+	return defaultRunner.ResolveCommit(ws, rev)
+}
 
-	return nil
+// ChangedThreadFiles returns the thread files (.threads/*.md) that differ
+// between two revisions.
+func ChangedThreadFiles(ws, fromRev, toRev string) ([]string, error) {
+	return defaultRunner.ChangedThreadFiles(ws, fromRev, toRev)
 }
 
 // AutoCommit stages and commits a file locally (does not push)
@@ -189,43 +148,15 @@ func isHex(s string) bool {
 	return true
 }
 
+// fileExists reports whether path exists, used to tell a genuinely new
+// file from one whose removal is already staged.
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-// FindDeletedThreadFiles returns paths of deleted thread files that are staged or in working tree
-// These are files matching .threads/*.md that show as deleted (D) in git status
-func FindDeletedThreadFiles(ws string) ([]string, error) {
-	cmd := exec.Command("git", "-C", ws, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("git status failed: %w", err)
-	}
-
-	var deleted []string
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if len(line) < 4 {
-			continue
-		}
-		// Porcelain format: XY PATH
-		// X = index status, Y = worktree status
-		// D in either position means deleted
-		indexStatus := line[0]
-		worktreeStatus := line[1]
-		path := line[3:]
-
-		// Check if it's a deletion and matches thread file pattern
-		if (indexStatus == 'D' || worktreeStatus == 'D') && isThreadPath(path) {
-			deleted = append(deleted, filepath.Join(ws, path))
-		}
-	}
-
-	return deleted, nil
-}
-
-// isThreadPath checks if a path looks like a thread file (.threads/*.md)
+// isThreadPath reports whether path looks like a thread file (.threads/*.md),
+// shared by every backend's FindDeletedThreadFiles.
 func isThreadPath(path string) bool {
 	return strings.Contains(path, ".threads/") && strings.HasSuffix(path, ".md")
 }
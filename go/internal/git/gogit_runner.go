@@ -0,0 +1,456 @@
+package git
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// goGitRunner implements Runner against an embedded pure-Go git
+// implementation (github.com/go-git/go-git/v5), so threads keeps working
+// in environments without a git binary on PATH (minimal containers,
+// sandboxed hooks, CI images). Opening a *git.Repository parses packfiles
+// and the index, so repoCache keeps one open per workspace: scanning
+// hundreds of thread files (QuickStatusAll and friends) would otherwise
+// reopen and re-parse the same repository once per file.
+type goGitRunner struct{}
+
+var repoCache = struct {
+	mu    sync.Mutex
+	repos map[string]*git.Repository
+}{repos: make(map[string]*git.Repository)}
+
+func (goGitRunner) open(ws string) (*git.Repository, error) {
+	repoCache.mu.Lock()
+	defer repoCache.mu.Unlock()
+
+	if repo, ok := repoCache.repos[ws]; ok {
+		return repo, nil
+	}
+
+	repo, err := git.PlainOpen(ws)
+	if err != nil {
+		return nil, fmt.Errorf("opening repository at %s: %w", ws, err)
+	}
+	repoCache.repos[ws] = repo
+	return repo, nil
+}
+
+// DiscoverRoot finds the work-tree root containing startPath by walking
+// parents for a .git entry, without shelling out to `git rev-parse`.
+func (goGitRunner) DiscoverRoot(startPath string) (string, error) {
+	repo, err := git.PlainOpenWithOptions(startPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository at %s: %w", startPath, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("resolving worktree root: %w", err)
+	}
+	return wt.Filesystem.Root(), nil
+}
+
+func (r goGitRunner) HasChanges(ws, relPath string) bool {
+	repo, err := r.open(ws)
+	if err != nil {
+		return true
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return true
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return true
+	}
+	s := status.File(relPath)
+	return s.Staging != git.Unmodified || s.Worktree != git.Unmodified
+}
+
+func (r goGitRunner) IsTracked(ws, relPath string) bool {
+	repo, err := r.open(ws)
+	if err != nil {
+		return false
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return false
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false
+	}
+	return status.File(relPath).Worktree != git.Untracked
+}
+
+func (r goGitRunner) ExistsInHEAD(ws, relPath string) bool {
+	repo, err := r.open(ws)
+	if err != nil {
+		return false
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return false
+	}
+	_, err = tree.File(relPath)
+	return err == nil
+}
+
+func (r goGitRunner) Add(ws string, files ...string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("opening worktree: %w", err)
+	}
+	for _, f := range files {
+		if _, err := wt.Add(f); err != nil {
+			return fmt.Errorf("git add %s failed: %w", f, err)
+		}
+	}
+	return nil
+}
+
+func (r goGitRunner) Commit(ws string, files []string, message string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("opening worktree: %w", err)
+	}
+	if err := r.Add(ws, files...); err != nil {
+		return err
+	}
+
+	sig, err := commitSignature(repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = wt.Commit(message, &git.CommitOptions{Author: sig})
+	if err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	return nil
+}
+
+// commitWithOptions commits like Commit, but with an explicit author
+// identity and/or a GPG signing key, for Client.CommitWithOptions.
+func (r goGitRunner) commitWithOptions(ws string, files []string, opts CommitOptions) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("opening worktree: %w", err)
+	}
+	if err := r.Add(ws, files...); err != nil {
+		return err
+	}
+
+	var sig *object.Signature
+	if opts.Author != "" {
+		sig, err = parseSignature(opts.Author)
+	} else {
+		sig, err = commitSignature(repo)
+	}
+	if err != nil {
+		return err
+	}
+
+	commitOpts := &git.CommitOptions{Author: sig}
+	if opts.SignKey != "" {
+		entity, err := loadSignKey(opts.SignKey)
+		if err != nil {
+			return err
+		}
+		commitOpts.SignKey = entity
+	}
+
+	_, err = wt.Commit(opts.Message, commitOpts)
+	if err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	return nil
+}
+
+// loadSignKey reads an armored PGP private key from path, for
+// commitWithOptions's --sign support.
+func loadSignKey(path string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sign key %s: %w", path, err)
+	}
+	defer f.Close()
+
+	entities, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading sign key %s: %w", path, err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no keys found in %s", path)
+	}
+	return entities[0], nil
+}
+
+// parseSignature parses an author string formatted "Name <email>" into a
+// commit signature timestamped now.
+func parseSignature(author string) (*object.Signature, error) {
+	addr, err := mail.ParseAddress(author)
+	if err != nil {
+		return nil, fmt.Errorf("invalid author %q, want \"Name <email>\": %w", author, err)
+	}
+	return &object.Signature{Name: addr.Name, Email: addr.Address, When: time.Now()}, nil
+}
+
+func (r goGitRunner) Push(ws string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	auth, err := remoteAuth(repo)
+	if err != nil {
+		return err
+	}
+	if err := repo.Fetch(&git.FetchOptions{Auth: auth}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+	if err := repo.Push(&git.PushOptions{Auth: auth}); err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("git push failed: %w", err)
+	}
+	return nil
+}
+
+func (r goGitRunner) FindDeletedThreadFiles(ws string) ([]string, error) {
+	repo, err := r.open(ws)
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("opening worktree: %w", err)
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+
+	var deleted []string
+	for path, s := range status {
+		if (s.Staging == git.Deleted || s.Worktree == git.Deleted) && isThreadPath(path) {
+			deleted = append(deleted, path)
+		}
+	}
+	return deleted, nil
+}
+
+// Blame walks relPath's history via go-git's commit-based blame, the
+// pure-Go equivalent of `git blame --line-porcelain`.
+func (r goGitRunner) Blame(ws, relPath string) ([]BlameLine, error) {
+	repo, err := r.open(ws)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD commit: %w", err)
+	}
+
+	result, err := git.Blame(commit, relPath)
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s failed: %w", relPath, err)
+	}
+
+	lines := make([]BlameLine, 0, len(result.Lines))
+	for i, l := range result.Lines {
+		lines = append(lines, BlameLine{
+			Line:   i + 1,
+			Commit: l.Hash.String(),
+			Author: l.Author,
+			Date:   l.Date.UTC().Format(time.RFC3339),
+		})
+	}
+	return lines, nil
+}
+
+// FetchRefspec fetches refspec from remote.
+func (r goGitRunner) FetchRefspec(ws, remote, refspec string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	auth, err := authForRemote(repo, remote)
+	if err != nil {
+		return err
+	}
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(refspec)},
+		Auth:       auth,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("git fetch %s %s failed: %w", remote, refspec, err)
+	}
+	return nil
+}
+
+// PushRefspec pushes refspec to remote.
+func (r goGitRunner) PushRefspec(ws, remote, refspec string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	auth, err := authForRemote(repo, remote)
+	if err != nil {
+		return err
+	}
+	err = repo.Push(&git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(refspec)},
+		Auth:       auth,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("git push %s %s failed: %w", remote, refspec, err)
+	}
+	return nil
+}
+
+// ConfigGet reads section.key from the repository's config.
+func (r goGitRunner) ConfigGet(ws, section, key string) (string, bool) {
+	repo, err := r.open(ws)
+	if err != nil {
+		return "", false
+	}
+	cfg, err := repo.ConfigScoped(0)
+	if err != nil {
+		return "", false
+	}
+	value := strings.TrimSpace(cfg.Raw.Section(section).Option(key))
+	if value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// ConfigSet writes section.key into the repository's local config.
+func (r goGitRunner) ConfigSet(ws, section, key, value string) error {
+	repo, err := r.open(ws)
+	if err != nil {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	cfg.Raw.Section(section).SetOption(key, value)
+	return repo.SetConfig(cfg)
+}
+
+// ResolveCommit resolves rev - HEAD, FETCH_HEAD, a branch, tag, or hash -
+// to its full commit hash in ws, the pure-Go equivalent of
+// `git rev-parse <rev>` for a single revision.
+func (r goGitRunner) ResolveCommit(ws, rev string) (string, error) {
+	repo, err := r.open(ws)
+	if err != nil {
+		return "", err
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", rev, err)
+	}
+	return hash.String(), nil
+}
+
+// ChangedThreadFiles returns the thread files (.threads/*.md) whose content
+// differs between fromRev and toRev in ws, the pure-Go equivalent of
+// `git diff --name-only <fromRev> <toRev> -- .threads`.
+func (r goGitRunner) ChangedThreadFiles(ws, fromRev, toRev string) ([]string, error) {
+	repo, err := r.open(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTree, err := revisionTree(repo, fromRev)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := revisionTree(repo, toRev)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := fromTree.Diff(toTree)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s..%s: %w", fromRev, toRev, err)
+	}
+
+	var paths []string
+	for _, c := range changes {
+		path := c.To.Name
+		if path == "" {
+			path = c.From.Name
+		}
+		if isThreadPath(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// revisionTree resolves rev to the tree of the commit it points at.
+func revisionTree(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("resolving commit %s: %w", rev, err)
+	}
+	return commit.Tree()
+}
+
+// commitSignature derives an author/committer signature from the
+// repository's configured user, falling back to a generic identity so
+// commits still succeed in environments without user.name/user.email set.
+func commitSignature(repo *git.Repository) (*object.Signature, error) {
+	cfg, err := repo.ConfigScoped(0)
+	if err != nil {
+		return &object.Signature{Name: "threads", Email: "threads@localhost", When: time.Now()}, nil
+	}
+	name := strings.TrimSpace(cfg.User.Name)
+	email := strings.TrimSpace(cfg.User.Email)
+	if name == "" {
+		name = "threads"
+	}
+	if email == "" {
+		email = "threads@localhost"
+	}
+	return &object.Signature{Name: name, Email: email, When: time.Now()}, nil
+}
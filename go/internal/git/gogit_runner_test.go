@@ -0,0 +1,43 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoGitRunnerResolveCommitAndChangedThreadFiles(t *testing.T) {
+	dir := setupBenchRepo(t, 1)
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	r := goGitRunner{}
+
+	firstHead, err := r.ResolveCommit(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("ResolveCommit(HEAD): %v", err)
+	}
+	if len(firstHead) != 40 {
+		t.Errorf("ResolveCommit(HEAD) = %q, want a 40-char hash", firstHead)
+	}
+
+	path := filepath.Join(dir, ".threads", "thread-0.md")
+	if err := os.WriteFile(path, []byte("# thread\n\nedited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("commit", "-q", "-am", "edit thread-0")
+
+	changed, err := r.ChangedThreadFiles(dir, firstHead, "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedThreadFiles: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != ".threads/thread-0.md" {
+		t.Errorf("ChangedThreadFiles() = %v, want [.threads/thread-0.md]", changed)
+	}
+}
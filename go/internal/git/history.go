@@ -0,0 +1,64 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// FileRevision is one commit that touched a file, together with the
+// file's full content as of that commit - the basis for diffing
+// consecutive ast.Documents in `threads history` instead of working from
+// a raw unified diff.
+type FileRevision struct {
+	Hash    string
+	Author  string
+	Date    string // RFC3339, from the commit's author time
+	Message string
+	Content string // file content as of this commit; "" if it didn't exist yet
+}
+
+// FileHistory returns relPath's revisions via the embedded go-git backend,
+// newest first, matching `git log`'s default order.
+func FileHistory(ws, relPath string) ([]FileRevision, error) {
+	repo, err := (goGitRunner{}).open(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash(), FileName: &relPath})
+	if err != nil {
+		return nil, fmt.Errorf("walking history of %s: %w", relPath, err)
+	}
+
+	var revs []FileRevision
+	err = commits.ForEach(func(c *object.Commit) error {
+		content := ""
+		if f, ferr := c.File(relPath); ferr == nil {
+			if s, serr := f.Contents(); serr == nil {
+				content = s
+			}
+		}
+		revs = append(revs, FileRevision{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Date:    c.Author.When.UTC().Format(time.RFC3339),
+			Message: strings.TrimSpace(c.Message),
+			Content: content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking history of %s: %w", relPath, err)
+	}
+
+	return revs, nil
+}
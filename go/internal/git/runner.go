@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"os"
+)
+
+// Runner performs the git operations needed by the cmd package against a
+// workspace. It exists so that a pure shell-out implementation and a
+// pure-Go (go-git) implementation can be swapped without touching callers.
+type Runner interface {
+	DiscoverRoot(startPath string) (string, error)
+	HasChanges(ws, relPath string) bool
+	IsTracked(ws, relPath string) bool
+	ExistsInHEAD(ws, relPath string) bool
+	Add(ws string, files ...string) error
+	Commit(ws string, files []string, message string) error
+	Push(ws string) error
+	FindDeletedThreadFiles(ws string) ([]string, error)
+	Blame(ws, relPath string) ([]BlameLine, error)
+	FetchRefspec(ws, remote, refspec string) error
+	PushRefspec(ws, remote, refspec string) error
+	ConfigGet(ws, section, key string) (string, bool)
+	ConfigSet(ws, section, key, value string) error
+	ResolveCommit(ws, rev string) (string, error)
+	ChangedThreadFiles(ws, fromRev, toRev string) ([]string, error)
+}
+
+// Backend identifies a Runner implementation.
+type Backend string
+
+const (
+	BackendExec  Backend = "exec"
+	BackendGoGit Backend = "gogit"
+)
+
+// defaultRunner backs the package-level helper functions (HasChanges, Add,
+// Commit, ...) below. SetBackend swaps it out; everything else in this
+// package and in internal/cmd keeps calling the free functions unchanged.
+var defaultRunner Runner = goGitRunner{}
+
+// SetBackend selects the Runner used by the package-level helpers. "gogit"
+// (the default) is an embedded, pure-Go implementation that needs no git
+// binary and opens each workspace's repository once, however many
+// operations a command performs. "exec" shells out to the git binary
+// instead; it's only available in binaries built with -tags cliexec, for
+// whoever needs git hooks, credential helpers, or signed commits that only
+// work through a real git install.
+func SetBackend(b Backend) error {
+	switch b {
+	case "", BackendGoGit:
+		defaultRunner = goGitRunner{}
+	case BackendExec:
+		r, err := newExecRunner()
+		if err != nil {
+			return err
+		}
+		defaultRunner = r
+	default:
+		return fmt.Errorf("unknown git backend %q (want %q or %q)", b, BackendExec, BackendGoGit)
+	}
+	return nil
+}
+
+// BackendFromEnv resolves the backend from THREADS_GIT_BACKEND, defaulting
+// to gogit if unset.
+func BackendFromEnv() Backend {
+	if v := os.Getenv("THREADS_GIT_BACKEND"); v != "" {
+		return Backend(v)
+	}
+	return BackendGoGit
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ShellRunner executes a single git invocation and returns stdout/stderr
+// separately. It's the seam Cmd.run uses to actually launch git, one layer
+// below Runner (which swaps whole backends like exec vs go-git): swapping
+// the ShellRunner lets internal/cmd's tests drive the exec backend itself
+// with canned output, without a real git binary or repository.
+type ShellRunner interface {
+	Run(ctx context.Context, argv []string, opts RunOpts) (stdout, stderr string, err error)
+}
+
+// shellRunnerImpl backs Cmd.run. SetShellRunner overrides it for tests.
+var shellRunnerImpl ShellRunner = RealShellRunner{}
+
+// SetShellRunner overrides the ShellRunner used by every Cmd. Tests should
+// restore the previous value (or call SetShellRunner(RealShellRunner{}))
+// when done, since it's shared, package-level state.
+func SetShellRunner(r ShellRunner) {
+	shellRunnerImpl = r
+}
+
+// RealShellRunner launches the actual git binary via os/exec.
+type RealShellRunner struct{}
+
+func (RealShellRunner) Run(ctx context.Context, argv []string, opts RunOpts) (stdout, stderr string, err error) {
+	cmd := exec.CommandContext(ctx, "git", argv...)
+	if len(opts.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), opts.Env...)
+	}
+	cmd.Stdin = opts.Stdin
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	runErr := cmd.Run()
+	return outBuf.String(), errBuf.String(), runErr
+}
+
+// FakeCall records one invocation seen by a FakeShellRunner, for assertions
+// in tests that don't care about stdout/stderr, only that the right git
+// command ran.
+type FakeCall struct {
+	Argv []string
+}
+
+// FakeResponse is the canned result for one matched invocation.
+type FakeResponse struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// FakeShellRunner is a ShellRunner for unit tests: it matches invocations by
+// their argv joined with spaces (see FakeShellRunner.key) and returns a
+// canned FakeResponse, so internal/cmd command handlers can be tested
+// without a real git repository.
+type FakeShellRunner struct {
+	mu        sync.Mutex
+	responses map[string]FakeResponse
+	calls     []FakeCall
+}
+
+// NewFakeShellRunner returns an empty FakeShellRunner. Register expected
+// invocations with On, then inspect Calls afterwards.
+func NewFakeShellRunner() *FakeShellRunner {
+	return &FakeShellRunner{responses: make(map[string]FakeResponse)}
+}
+
+// On registers the response to return when argv matches exactly (ignoring
+// the leading "-C <dir>" pair, so the same expectation works regardless of
+// which workspace path a test uses).
+func (f *FakeShellRunner) On(argv []string, resp FakeResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[f.key(argv)] = resp
+}
+
+// Calls returns every invocation seen so far, in order.
+func (f *FakeShellRunner) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeCall{}, f.calls...)
+}
+
+func (f *FakeShellRunner) key(argv []string) string {
+	// Drop "-C <dir>" so expectations don't need to know the test's
+	// temp workspace path.
+	if len(argv) >= 2 && argv[0] == "-C" {
+		argv = argv[2:]
+	}
+	return strings.Join(argv, " ")
+}
+
+func (f *FakeShellRunner) Run(ctx context.Context, argv []string, opts RunOpts) (stdout, stderr string, err error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeCall{Argv: append([]string{}, argv...)})
+	resp, ok := f.responses[f.key(argv)]
+	f.mu.Unlock()
+
+	if !ok {
+		return "", "", fmt.Errorf("FakeShellRunner: no response registered for %q", strings.Join(argv, " "))
+	}
+	return resp.Stdout, resp.Stderr, resp.Err
+}
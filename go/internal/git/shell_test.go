@@ -0,0 +1,36 @@
+//go:build cliexec
+
+package git
+
+import "testing"
+
+func TestFakeShellRunnerDrivesHasChanges(t *testing.T) {
+	fake := NewFakeShellRunner()
+	fake.On([]string{"diff", "--quiet", "--", "thread.md"}, FakeResponse{})
+	fake.On([]string{"diff", "--cached", "--quiet", "--", "thread.md"}, FakeResponse{})
+	fake.On([]string{"ls-files", "--error-unmatch", "--", "thread.md"}, FakeResponse{})
+
+	prev := shellRunnerImpl
+	SetShellRunner(fake)
+	defer SetShellRunner(prev)
+
+	if (execRunner{}).HasChanges("/repo", "thread.md") {
+		t.Errorf("HasChanges() = true, want false when every check succeeds")
+	}
+
+	if got := len(fake.Calls()); got != 3 {
+		t.Errorf("Calls() len = %d, want 3", got)
+	}
+}
+
+func TestFakeShellRunnerUnregisteredCallFails(t *testing.T) {
+	fake := NewFakeShellRunner()
+
+	prev := shellRunnerImpl
+	SetShellRunner(fake)
+	defer SetShellRunner(prev)
+
+	if (execRunner{}).IsTracked("/repo", "thread.md") {
+		t.Errorf("IsTracked() = true, want false for an unregistered (failing) invocation")
+	}
+}
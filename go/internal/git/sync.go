@@ -0,0 +1,26 @@
+package git
+
+// FetchRefspec fetches refspec from remote (e.g.
+// "refs/heads/*:refs/threads/*"), the basis for `threads sync --pull`.
+func FetchRefspec(ws, remote, refspec string) error {
+	return defaultRunner.FetchRefspec(ws, remote, refspec)
+}
+
+// PushRefspec pushes refspec to remote, the basis for `threads sync --push`.
+func PushRefspec(ws, remote, refspec string) error {
+	return defaultRunner.PushRefspec(ws, remote, refspec)
+}
+
+// ConfigGet reads a git config value (e.g. section "threads", key "remote"
+// for `[threads]\n\tremote = ...`). The second return value is false if the
+// key isn't set.
+func ConfigGet(ws, section, key string) (string, bool) {
+	return defaultRunner.ConfigGet(ws, section, key)
+}
+
+// ConfigSet writes a git config value, e.g. section "merge.threads-md", key
+// "driver" for the `[merge "threads-md"]\n\tdriver = ...` entry a custom
+// merge driver registration needs.
+func ConfigSet(ws, section, key, value string) error {
+	return defaultRunner.ConfigSet(ws, section, key, value)
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Worktree materializes a temporary, isolated git worktree so that bulk
+// thread mutations (threads commit --pending, threads resolve --worktree)
+// can be staged and committed without touching whatever else the user has
+// dirty in their main working copy, and without two concurrent `threads`
+// invocations racing on the same index.
+type Worktree struct {
+	originalGitPath string // the workspace the worktree was created from
+	worktreePath    string // the isolated checkout
+}
+
+// NewWorktree creates a worktree checked out from HEAD of ws, detached so
+// it doesn't collide with whatever branch is checked out in ws itself.
+func NewWorktree(ws string) (*Worktree, error) {
+	return NewWorktreeFromRef(ws, "HEAD")
+}
+
+// NewWorktreeFromRef creates a worktree checked out from baseRef of ws,
+// detached so it doesn't collide with whatever branch is checked out in ws
+// itself.
+func NewWorktreeFromRef(ws, baseRef string) (*Worktree, error) {
+	dir, err := os.MkdirTemp("", "threads-worktree-")
+	if err != nil {
+		return nil, fmt.Errorf("creating worktree tmpdir: %w", err)
+	}
+
+	if _, err := NewCmd(ws, "worktree").AddOption("add", "--detach").AddDashedValue(dir, baseRef).Run(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("git worktree add failed: %w", err)
+	}
+
+	return &Worktree{originalGitPath: ws, worktreePath: dir}, nil
+}
+
+// WorktreePath returns the isolated checkout's path, for callers that need
+// to write thread files into it before committing.
+func (w *Worktree) WorktreePath() string {
+	return w.worktreePath
+}
+
+// Path is an alias for WorktreePath.
+func (w *Worktree) Path() string {
+	return w.worktreePath
+}
+
+// CopyIn copies a file from the original workspace into the worktree,
+// preserving its path relative to the git root.
+func (w *Worktree) CopyIn(relPath string) error {
+	src := filepath.Join(w.originalGitPath, relPath)
+	dst := filepath.Join(w.worktreePath, relPath)
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// Close removes the worktree checkout and prunes its administrative
+// metadata from the original repository.
+func (w *Worktree) Close() error {
+	if _, err := NewCmd(w.originalGitPath, "worktree").AddOption("remove", "--force").AddDashedValue(w.worktreePath).Run(); err != nil {
+		// Fall back to a manual removal + prune if the checkout is already gone
+		os.RemoveAll(w.worktreePath)
+	}
+	_, err := NewCmd(w.originalGitPath, "worktree").AddOption("prune").Run()
+	return err
+}
+
+// MergeBack cherry-picks the given commit (created inside the worktree)
+// onto the branch currently checked out in the original workspace.
+func MergeBack(ws, commitHash string) error {
+	_, err := NewCmd(ws, "cherry-pick").AddDashedValue(commitHash).Run()
+	return err
+}
+
+// HeadCommit returns the hash of HEAD in the given checkout.
+func HeadCommit(ws string) (string, error) {
+	out, err := NewCmd(ws, "rev-parse").AddValue("HEAD").Run()
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(out), nil
+}
+
+// ConflictStage selects which side of a 3-way merge conflict to read.
+type ConflictStage int
+
+const (
+	StageBase   ConflictStage = 1
+	StageOurs   ConflictStage = 2
+	StageTheirs ConflictStage = 3
+)
+
+// ReadStage returns relPath's content at the given conflict stage from the
+// index (1=base, 2=ours, 3=theirs), as populated by git during an unresolved
+// merge. Returns "" if that stage doesn't exist (e.g. the file was added on
+// only one side).
+func ReadStage(ws, relPath string, stage ConflictStage) (string, error) {
+	ref := fmt.Sprintf(":%d:%s", stage, relPath)
+	out, err := NewCmd(ws, "show").AddValue(ref).Output()
+	if err != nil {
+		return "", nil
+	}
+	return out, nil
+}
+
+func trimNewline(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
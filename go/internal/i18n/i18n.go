@@ -0,0 +1,132 @@
+// Package i18n extracts threads' user-facing strings from the cmd package
+// so they can be translated. Catalogs are plain gettext .po files under
+// locales/; T looks a key up in the active locale's catalog and falls back
+// to the literal key (treated as the English source string) when no
+// catalog, or no entry, is found.
+//
+// Coverage is currently partial, not package-wide: i18n.T was adopted by
+// new/update/status-family commands present through chunk2-5
+// (body/commit/log/move/new/note/reopen/resolve/stats/status/todo/update),
+// and the "i18n-extract" Makefile target only catches i18n.T call sites
+// missing a catalog entry - it can't see a command that never calls i18n.T
+// at all. Commands added afterward (validate, hooks, merge-driver, git,
+// history, batch, sync, worktree, tui, and friends) still print hardcoded
+// English and are not yet converted. Extending coverage to them is
+// tracked as follow-up work, not assumed done.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+//go:embed locales/*.po
+var catalogFS embed.FS
+
+var active = message.NewPrinter(language.English)
+
+// SetLocale loads the catalog for the given locale (e.g. "de", "de_DE") and
+// makes it active. An empty locale, or one with no matching catalog, falls
+// back to English (the literal keys passed to T).
+func SetLocale(locale string) error {
+	if locale == "" {
+		active = message.NewPrinter(language.English)
+		return nil
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+
+	entries, err := loadCatalog(locale)
+	if err != nil {
+		// No catalog shipped for this locale: fall back to English keys
+		// rather than failing the command outright.
+		active = message.NewPrinter(language.English)
+		return nil
+	}
+
+	for key, translated := range entries {
+		if err := message.SetString(tag, key, translated); err != nil {
+			return fmt.Errorf("locale %q: %w", locale, err)
+		}
+	}
+	active = message.NewPrinter(tag)
+	return nil
+}
+
+// ResolveLocale picks a locale from an explicit --locale flag value,
+// falling back to THREADS_LANG, then LC_ALL/LC_MESSAGES/LANG.
+func ResolveLocale(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	for _, env := range []string{"THREADS_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return normalizeLocaleEnv(v)
+		}
+	}
+	return ""
+}
+
+// normalizeLocaleEnv strips encoding suffixes from POSIX-style locale env
+// values, e.g. "de_DE.UTF-8" -> "de_DE".
+func normalizeLocaleEnv(v string) string {
+	if i := strings.IndexByte(v, '.'); i != -1 {
+		v = v[:i]
+	}
+	return v
+}
+
+// T formats a message identified by its English source string (used
+// directly as the catalog key, gettext-style) with the active locale's
+// translation, or the literal key if none is active/found.
+func T(key string, args ...any) string {
+	return active.Sprintf(key, args...)
+}
+
+// loadCatalog reads locales/<locale>.po and returns its msgid -> msgstr
+// entries. Only the subset of the PO format threads' catalogs use is
+// supported: single-line, non-plural msgid/msgstr pairs.
+func loadCatalog(locale string) (map[string]string, error) {
+	data, err := catalogFS.ReadFile("locales/" + locale + ".po")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string]string{}
+	var msgid string
+	haveID := false
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "msgid "):
+			msgid = unquote(strings.TrimPrefix(line, "msgid "))
+			haveID = true
+		case strings.HasPrefix(line, "msgstr ") && haveID:
+			msgstr := unquote(strings.TrimPrefix(line, "msgstr "))
+			if msgid != "" && msgstr != "" {
+				entries[msgid] = msgstr
+			}
+			haveID = false
+		}
+	}
+
+	return entries, nil
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, `"`)
+	s = strings.TrimSuffix(s, `"`)
+	s = strings.ReplaceAll(s, `\"`, `"`)
+	s = strings.ReplaceAll(s, `\n`, "\n")
+	return s
+}
@@ -0,0 +1,355 @@
+// Package ast parses a thread file into a typed document instead of
+// scanning it line-by-line with regexps, so constructs regexp-based
+// parsing gets wrong - a "## Foo" inside a fenced code block, a heading
+// with inline code, CRLF line endings - parse correctly. It's built on
+// goldmark, a CommonMark-compliant parser, for the block structure; leaf
+// content (a note's text, a todo's checkbox, a log entry's timestamp)
+// is still matched with small, narrowly-scoped patterns, but those now run
+// against text goldmark has already isolated into the right block, not
+// against raw file lines.
+package ast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gmast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// minHashLen is the shortest content-addressed hash prefix an item is ever
+// tagged with; kept in sync with thread.minHashLen by hand (duplicated,
+// like fullHash/generateHash below, to avoid a cyclic import).
+const minHashLen = 4
+
+// Document is a parsed thread file: the raw frontmatter block (threads
+// doesn't need the AST to touch YAML, so it's kept verbatim) plus any free
+// text before the first heading, followed by its "## " sections.
+type Document struct {
+	FrontmatterRaw string // "---\n...\n---\n", verbatim, or "" if absent
+	Preamble       string // free text between frontmatter and the first heading
+	Sections       []*Section
+}
+
+// Section is one "## Name" heading and the blocks under it.
+type Section struct {
+	Name   string
+	Blocks []Block
+}
+
+// Block is one parsed unit of section content. The concrete type depends
+// on the section it was found in: Note and TodoItem in Notes/Todo, LogDay
+// in Log, Raw anywhere goldmark found something threads doesn't model
+// (plain prose, a sub-heading, a table - preserved verbatim on Render).
+type Block interface {
+	render() string
+}
+
+// Note is one hash-tagged line in a Notes section.
+type Note struct {
+	Hash string
+	Text string
+}
+
+func (n *Note) render() string {
+	return fmt.Sprintf("- %s  <!-- %s -->", n.Text, n.Hash)
+}
+
+// TodoItem is one hash-tagged checkbox line in a Todo section.
+type TodoItem struct {
+	Hash    string
+	Text    string
+	Checked bool
+}
+
+func (t *TodoItem) render() string {
+	box := " "
+	if t.Checked {
+		box = "x"
+	}
+	return fmt.Sprintf("- [%s] %s  <!-- %s -->", box, t.Text, t.Hash)
+}
+
+// LogEntry is one timestamped bullet within a LogDay.
+type LogEntry struct {
+	Time string
+	Text string
+}
+
+// LogDay is a "### YYYY-MM-DD" heading and its bullets, the unit Log is
+// grouped into.
+type LogDay struct {
+	Date    string
+	Entries []LogEntry
+}
+
+func (d *LogDay) render() string {
+	var sb strings.Builder
+	sb.WriteString("### " + d.Date + "\n\n")
+	for _, e := range d.Entries {
+		sb.WriteString(fmt.Sprintf("- **%s** %s\n", e.Time, e.Text))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// Raw is verbatim markdown threads doesn't parse further.
+type Raw struct {
+	Text string
+}
+
+func (r *Raw) render() string { return r.Text }
+
+var (
+	// hashCommentRe matches an item's content-addressed hash tag; items
+	// normally carry minHashLen characters, more only when thread.Index
+	// reports that's needed to stay unique workspace-wide, so the length
+	// isn't fixed.
+	hashCommentRe = regexp.MustCompile(`<!--\s*([a-f0-9]{4,})\s*-->`)
+	todoLineRe    = regexp.MustCompile(`^\[([ xX])\]\s*(.+?)\s*(<!--\s*[a-f0-9]{4,}\s*-->)?$`)
+	logEntryRe    = regexp.MustCompile(`^\*\*(\d{2}:\d{2})\*\*\s*(.*)$`)
+)
+
+// md parses plain CommonMark, deliberately without the GFM task-list
+// extension: that extension would replace a "[ ]"/"[x]" checkbox with its
+// own AST node instead of leaving it as literal text, and todoLineRe below
+// is simpler matching the literal text goldmark gives a plain list item.
+var md = goldmark.New()
+
+// Parse splits raw thread content into frontmatter, preamble, and
+// goldmark-parsed sections.
+func Parse(content string) (*Document, error) {
+	fm, body, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{FrontmatterRaw: fm}
+
+	source := []byte(body)
+	root := md.Parser().Parse(text.NewReader(source))
+
+	var current *Section
+	logDate := ""
+	for n := root.FirstChild(); n != nil; n = n.NextSibling() {
+		if h, ok := n.(*gmast.Heading); ok && h.Level == 2 {
+			current = &Section{Name: nodeText(h, source)}
+			doc.Sections = append(doc.Sections, current)
+			logDate = ""
+			continue
+		}
+
+		if current == nil {
+			doc.Preamble += rawLines(n, source) + "\n\n"
+			continue
+		}
+
+		// Log groups by "### YYYY-MM-DD" heading, so it needs the heading
+		// text threaded into the list that follows it - every other
+		// section's blocks come from a single node in isolation.
+		if current.Name == "Log" {
+			if h, ok := n.(*gmast.Heading); ok && h.Level == 3 {
+				logDate = strings.TrimSpace(nodeText(h, source))
+				continue
+			}
+			if list, ok := n.(*gmast.List); ok {
+				if entries := parseLogEntries(list, source); len(entries) > 0 {
+					current.Blocks = append(current.Blocks, &LogDay{Date: logDate, Entries: entries})
+				}
+				continue
+			}
+			current.Blocks = append(current.Blocks, &Raw{Text: rawLines(n, source)})
+			continue
+		}
+
+		current.Blocks = append(current.Blocks, parseBlock(current.Name, n, source)...)
+	}
+
+	doc.Preamble = strings.TrimRight(doc.Preamble, "\n")
+	if doc.Preamble != "" {
+		doc.Preamble += "\n\n"
+	}
+
+	return doc, nil
+}
+
+// splitFrontmatter separates the "---\n...\n---\n" block (returned
+// verbatim) from the rest of the content. Content without a frontmatter
+// delimiter is treated as having none, and is parsed as-is.
+func splitFrontmatter(content string) (frontmatter, body string, err error) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content, nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return "", "", fmt.Errorf("unclosed frontmatter")
+	}
+	closeIdx := 4 + end + 4 // past opening ---\n, the yaml, and \n---
+	frontmatter = content[:closeIdx]
+	if !strings.HasPrefix(frontmatter, "---\n") {
+		frontmatter += "\n"
+	}
+	if strings.HasPrefix(content[closeIdx:], "\n") {
+		body = content[closeIdx+1:]
+	} else {
+		body = content[closeIdx:]
+	}
+	return frontmatter + "\n", body, nil
+}
+
+// parseBlock turns one top-level goldmark node under a section into zero
+// or more Blocks, interpreting list items according to the section's name.
+func parseBlock(sectionName string, n gmast.Node, source []byte) []Block {
+	list, ok := n.(*gmast.List)
+	if !ok {
+		return []Block{&Raw{Text: rawLines(n, source)}}
+	}
+
+	switch sectionName {
+	case "Notes":
+		var blocks []Block
+		for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+			line := strings.TrimSpace(rawLines(item, source))
+			hash := ""
+			if m := hashCommentRe.FindStringSubmatch(line); m != nil {
+				hash = m[1]
+			}
+			text := strings.TrimSpace(hashCommentRe.ReplaceAllString(line, ""))
+			blocks = append(blocks, &Note{Hash: hash, Text: text})
+		}
+		return blocks
+
+	case "Todo":
+		var blocks []Block
+		for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+			line := strings.TrimSpace(rawLines(item, source))
+			if m := todoLineRe.FindStringSubmatch(line); m != nil {
+				hash := ""
+				if hm := hashCommentRe.FindStringSubmatch(m[3]); hm != nil {
+					hash = hm[1]
+				}
+				blocks = append(blocks, &TodoItem{Hash: hash, Text: m[2], Checked: strings.EqualFold(m[1], "x")})
+				continue
+			}
+			blocks = append(blocks, &Raw{Text: "- " + line})
+		}
+		return blocks
+
+	default:
+		return []Block{&Raw{Text: rawLines(n, source)}}
+	}
+}
+
+// parseLogEntries reads a Log day's bulleted list into LogEntry values,
+// used directly by Parse (Log needs the preceding "### date" heading
+// threaded in, so it isn't driven through parseBlock like other sections).
+func parseLogEntries(list *gmast.List, source []byte) []LogEntry {
+	var entries []LogEntry
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		line := strings.TrimSpace(rawLines(item, source))
+		if m := logEntryRe.FindStringSubmatch(line); m != nil {
+			entries = append(entries, LogEntry{Time: m[1], Text: m[2]})
+		}
+	}
+	return entries
+}
+
+// nodeText concatenates the literal text of n's descendants, the usual way
+// to read a heading's or list item's rendered text out of a goldmark tree.
+func nodeText(n gmast.Node, source []byte) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch v := c.(type) {
+		case *gmast.Text:
+			sb.Write(v.Segment.Value(source))
+		case *gmast.RawHTML:
+			for i := 0; i < v.Segments.Len(); i++ {
+				seg := v.Segments.At(i)
+				sb.Write(seg.Value(source))
+			}
+		default:
+			sb.WriteString(nodeText(c, source))
+		}
+	}
+	return sb.String()
+}
+
+// rawLines returns n's exact source text, line by line, for content this
+// package doesn't model further (Body prose, an unrecognized block), and for
+// list items: unlike nodeText it reads bytes straight from source rather
+// than reconstructing them from inline nodes, so markdown syntax goldmark
+// parses out of the text (the "**" around a log entry's timestamp) survives.
+// Container nodes (List, ListItem, ...) carry no lines of their own, so
+// those recurse into their children instead.
+func rawLines(n gmast.Node, source []byte) string {
+	if withLines, ok := n.(interface{ Lines() *text.Segments }); ok {
+		if lines := withLines.Lines(); lines != nil && lines.Len() > 0 {
+			var sb strings.Builder
+			for i := 0; i < lines.Len(); i++ {
+				seg := lines.At(i)
+				sb.Write(seg.Value(source))
+			}
+			return strings.TrimRight(sb.String(), "\n")
+		}
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(rawLines(c, source))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// Render re-serializes the document deterministically: frontmatter,
+// preamble, then each section as "## Name" followed by its blocks.
+func (d *Document) Render() string {
+	var sb strings.Builder
+	sb.WriteString(d.FrontmatterRaw)
+	sb.WriteString(d.Preamble)
+
+	for i, sec := range d.Sections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("## " + sec.Name + "\n\n")
+		if sec.Name == "Log" {
+			for j, b := range sec.Blocks {
+				if j > 0 {
+					sb.WriteString("\n\n")
+				}
+				sb.WriteString(b.render())
+			}
+			sb.WriteString("\n")
+			continue
+		}
+		for _, b := range sec.Blocks {
+			sb.WriteString(b.render())
+			sb.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// fullHash computes the complete 64-character content-addressed digest for
+// an item, the same formula thread.NewItemID uses (a SHA-256 digest over
+// its section and normalized text, so the same text always yields the same
+// ID): duplicated rather than imported to keep this package free of a
+// cyclic dependency on its parent (which uses ast as a parsing backend and
+// grows a hash's prefix via thread.Index when a shorter one would collide).
+func fullHash(section, text string) string {
+	norm := strings.Join(strings.Fields(text), " ")
+	sum := sha256.Sum256([]byte(section + "\x00" + norm))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateHash returns an item's default, minimum-length hash tag.
+func generateHash(section, text string) string {
+	return fullHash(section, text)[:minHashLen]
+}
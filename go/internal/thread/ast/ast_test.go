@@ -0,0 +1,149 @@
+package ast
+
+import "testing"
+
+const sampleThread = `---
+id: abc123
+name: test thread
+desc: a thread
+status: active
+---
+## Todo
+
+- [ ] buy milk  <!-- aaaa -->
+- [x] done thing  <!-- bbbb -->
+
+## Log
+
+### 2026-07-20
+
+- **09:00** Created thread.
+`
+
+func TestParseRenderRoundTrip(t *testing.T) {
+	doc, err := Parse(sampleThread)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := doc.Render(); got != sampleThread {
+		t.Errorf("Render() round trip mismatch:\ngot:\n%s\nwant:\n%s", got, sampleThread)
+	}
+}
+
+func TestParseTodoItems(t *testing.T) {
+	doc, err := Parse(sampleThread)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	todo := doc.Section("Todo")
+	if todo == nil || len(todo.Blocks) != 2 {
+		t.Fatalf("want 2 Todo blocks, got %v", todo)
+	}
+
+	first, ok := todo.Blocks[0].(*TodoItem)
+	if !ok || first.Hash != "aaaa" || first.Text != "buy milk" || first.Checked {
+		t.Errorf("unexpected first todo item: %+v", first)
+	}
+
+	second, ok := todo.Blocks[1].(*TodoItem)
+	if !ok || second.Hash != "bbbb" || !second.Checked {
+		t.Errorf("unexpected second todo item: %+v", second)
+	}
+}
+
+func TestParseLogEntryPreservesBoldTimestamp(t *testing.T) {
+	doc, err := Parse(sampleThread)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	log := doc.Section("Log")
+	if log == nil || len(log.Blocks) != 1 {
+		t.Fatalf("want 1 Log block, got %v", log)
+	}
+
+	day, ok := log.Blocks[0].(*LogDay)
+	if !ok || day.Date != "2026-07-20" || len(day.Entries) != 1 {
+		t.Fatalf("unexpected log day: %+v", day)
+	}
+	if day.Entries[0].Time != "09:00" || day.Entries[0].Text != "Created thread." {
+		t.Errorf("unexpected log entry: %+v", day.Entries[0])
+	}
+}
+
+func TestAddNoteCreatesSectionBeforeTodo(t *testing.T) {
+	doc, err := Parse(sampleThread)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	hash := doc.AddNote("first note")
+	if hash == "" {
+		t.Fatal("AddNote returned empty hash")
+	}
+	if len(doc.Sections) != 3 || doc.Sections[0].Name != "Notes" {
+		t.Fatalf("want Notes inserted before Todo, got %v", doc.Sections)
+	}
+
+	note, ok := doc.Sections[0].Blocks[0].(*Note)
+	if !ok || note.Hash != hash || note.Text != "first note" {
+		t.Errorf("unexpected note: %+v", note)
+	}
+}
+
+func TestSetTodoCheckedAndRemoveByHash(t *testing.T) {
+	doc, err := Parse(sampleThread)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if err := doc.SetTodoChecked("aaaa", true); err != nil {
+		t.Fatalf("SetTodoChecked: %v", err)
+	}
+	todo := doc.Section("Todo")
+	if item := todo.Blocks[0].(*TodoItem); !item.Checked {
+		t.Errorf("want item aaaa checked")
+	}
+
+	if err := doc.RemoveByHash("Todo", "bbbb"); err != nil {
+		t.Fatalf("RemoveByHash: %v", err)
+	}
+	if len(todo.Blocks) != 1 {
+		t.Fatalf("want 1 remaining Todo block, got %d", len(todo.Blocks))
+	}
+
+	if err := doc.RemoveByHash("Todo", "cccc"); err == nil {
+		t.Error("want error removing unknown hash")
+	}
+}
+
+func TestInsertLogEntryGroupsByDay(t *testing.T) {
+	const noLog = `---
+id: abc123
+name: test thread
+desc: a thread
+status: active
+---
+## Todo
+`
+	doc, err := Parse(noLog)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	doc.InsertLogEntry("first entry")
+	doc.InsertLogEntry("second entry")
+
+	log := doc.Section("Log")
+	if log == nil || len(log.Blocks) != 1 {
+		t.Fatalf("want a single LogDay, got %v", log)
+	}
+	day, ok := log.Blocks[0].(*LogDay)
+	if !ok {
+		t.Fatalf("want LogDay, got %T", log.Blocks[0])
+	}
+	if len(day.Entries) != 2 || day.Entries[0].Text != "second entry" || day.Entries[1].Text != "first entry" {
+		t.Errorf("want newest entry prepended, got %+v", day.Entries)
+	}
+}
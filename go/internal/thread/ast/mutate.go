@@ -0,0 +1,200 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Section returns the named section, or nil if the document has none.
+func (d *Document) Section(name string) *Section {
+	for _, s := range d.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// EnsureSection returns the named section, creating it (empty) just before
+// the "before" section if it doesn't exist yet, or at the end of the
+// document if "before" isn't present either.
+func (d *Document) EnsureSection(name, before string) *Section {
+	if s := d.Section(name); s != nil {
+		return s
+	}
+
+	sec := &Section{Name: name}
+	for i, s := range d.Sections {
+		if s.Name == before {
+			d.Sections = append(d.Sections, nil)
+			copy(d.Sections[i+1:], d.Sections[i:])
+			d.Sections[i] = sec
+			return sec
+		}
+	}
+
+	d.Sections = append(d.Sections, sec)
+	return sec
+}
+
+// AddNote prepends a new hash-tagged Note to the Notes section, creating the
+// section (before Todo) if it doesn't exist, and returns the note's hash.
+func (d *Document) AddNote(text string) string {
+	sec := d.EnsureSection("Notes", "Todo")
+	hash := generateHash("Notes", text)
+	sec.Blocks = append([]Block{&Note{Hash: hash, Text: text}}, sec.Blocks...)
+	return hash
+}
+
+// AddTodoItem prepends a new hash-tagged, unchecked TodoItem to the Todo
+// section, creating the section (before Log) if it doesn't exist, and
+// returns the item's hash.
+func (d *Document) AddTodoItem(text string) string {
+	sec := d.EnsureSection("Todo", "Log")
+	hash := generateHash("Todo", text)
+	sec.Blocks = append([]Block{&TodoItem{Hash: hash, Text: text}}, sec.Blocks...)
+	return hash
+}
+
+// SetTodoChecked sets the checked state of the Todo item matching the given
+// hash prefix, returning an error if no item, or more than one, matches.
+func (d *Document) SetTodoChecked(hash string, checked bool) error {
+	sec := d.Section("Todo")
+	if sec == nil {
+		return fmt.Errorf("no item with hash '%s' found", hash)
+	}
+	item, err := findItem(sec, hash)
+	if err != nil {
+		return err
+	}
+	item.(*TodoItem).Checked = checked
+	return nil
+}
+
+// RemoveByHash removes the Note or TodoItem matching the given hash prefix
+// from section, returning an error if no item, or more than one, matches.
+func (d *Document) RemoveByHash(section, hash string) error {
+	sec := d.Section(section)
+	if sec == nil {
+		return fmt.Errorf("no item with hash '%s' found", hash)
+	}
+	item, err := findItem(sec, hash)
+	if err != nil {
+		return err
+	}
+	for i, b := range sec.Blocks {
+		if b == item {
+			sec.Blocks = append(sec.Blocks[:i], sec.Blocks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no item with hash '%s' found", hash)
+}
+
+// EditByHash replaces the text of the Note or TodoItem matching the given
+// hash prefix in section, returning an error if no item, or more than one,
+// matches.
+func (d *Document) EditByHash(section, hash, newText string) error {
+	sec := d.Section(section)
+	if sec == nil {
+		return fmt.Errorf("no item with hash '%s' found", hash)
+	}
+	item, err := findItem(sec, hash)
+	if err != nil {
+		return err
+	}
+	switch v := item.(type) {
+	case *Note:
+		v.Text = newText
+	case *TodoItem:
+		v.Text = newText
+	}
+	return nil
+}
+
+// GrowItemHash re-derives the full content-addressed hash for the Note or
+// TodoItem currently tagged with the given hash prefix in section and
+// re-tags it with an n-character prefix instead, for when thread.Index
+// reports that a longer prefix is needed to stay unique workspace-wide.
+func (d *Document) GrowItemHash(section, hash string, n int) error {
+	sec := d.Section(section)
+	if sec == nil {
+		return fmt.Errorf("no item with hash '%s' found", hash)
+	}
+	item, err := findItem(sec, hash)
+	if err != nil {
+		return err
+	}
+
+	var full string
+	switch v := item.(type) {
+	case *Note:
+		full = fullHash(section, v.Text)
+	case *TodoItem:
+		full = fullHash(section, v.Text)
+	}
+	if n > len(full) {
+		n = len(full)
+	}
+
+	switch v := item.(type) {
+	case *Note:
+		v.Hash = full[:n]
+	case *TodoItem:
+		v.Hash = full[:n]
+	}
+	return nil
+}
+
+// findItem returns the single Note or TodoItem in sec whose hash has
+// prefix, erroring if none or more than one match - the same ambiguity git
+// itself reports for an underspecified abbreviated SHA.
+func findItem(sec *Section, prefix string) (Block, error) {
+	var match Block
+	for _, b := range sec.Blocks {
+		h := blockHash(b)
+		if h == "" || !strings.HasPrefix(h, prefix) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("ambiguous hash '%s' matches multiple items", prefix)
+		}
+		match = b
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no item with hash '%s' found", prefix)
+	}
+	return match, nil
+}
+
+// InsertLogEntry prepends a timestamped entry to today's LogDay, creating
+// both the Log section and today's heading if they don't exist yet.
+func (d *Document) InsertLogEntry(entry string) {
+	sec := d.EnsureSection("Log", "")
+	today := time.Now().Format("2006-01-02")
+	timestamp := time.Now().Format("15:04")
+
+	for _, b := range sec.Blocks {
+		if day, ok := b.(*LogDay); ok && day.Date == today {
+			day.Entries = append([]LogEntry{{Time: timestamp, Text: entry}}, day.Entries...)
+			return
+		}
+	}
+
+	day := &LogDay{Date: today, Entries: []LogEntry{{Time: timestamp, Text: entry}}}
+	sec.Blocks = append([]Block{day}, sec.Blocks...)
+}
+
+// blockHash returns a Note's or TodoItem's hash, or "" for block types that
+// don't carry one (LogDay, Raw).
+func blockHash(b Block) string {
+	switch v := b.(type) {
+	case *Note:
+		return v.Hash
+	case *TodoItem:
+		return v.Hash
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,132 @@
+package thread
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+)
+
+// BlameEntry attributes one frontmatter field, Note, Todo item, or Log
+// entry to the commit that last touched its line.
+type BlameEntry struct {
+	Section string `json:"section" yaml:"section"` // "frontmatter", "Notes", "Todo", "Log"
+	Key     string `json:"key" yaml:"key"`         // field name, item hash, or "<date> <time>" for Log
+	Status  string `json:"status,omitempty" yaml:"status,omitempty"`
+	Text    string `json:"text" yaml:"text"`
+	Author  string `json:"author" yaml:"author"`
+	Date    string `json:"date" yaml:"date"`
+}
+
+var frontmatterFieldRe = regexp.MustCompile(`^(\w+):\s*(.*)$`)
+
+// BlameItems walks path's git history via git.Blame and maps each
+// frontmatter field, Note, Todo item, and Log entry to the commit that
+// introduced or last modified its line.
+func BlameItems(ws, path string) ([]BlameEntry, error) {
+	t, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := filepath.Rel(ws, path)
+	if err != nil {
+		relPath = path
+	}
+
+	blameLines, err := git.Blame(ws, relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	byLine := make(map[int]git.BlameLine, len(blameLines))
+	for _, bl := range blameLines {
+		byLine[bl.Line] = bl
+	}
+
+	var entries []BlameEntry
+	inFrontmatter := false
+	section := ""
+	logDate := ""
+
+	for i, line := range strings.Split(t.Content, "\n") {
+		bl := byLine[i+1]
+
+		if line == "---" {
+			inFrontmatter = !inFrontmatter
+			continue
+		}
+
+		if inFrontmatter {
+			if m := frontmatterFieldRe.FindStringSubmatch(line); m != nil {
+				entries = append(entries, BlameEntry{
+					Section: "frontmatter",
+					Key:     m[1],
+					Text:    m[2],
+					Author:  bl.Author,
+					Date:    bl.Date,
+				})
+			}
+			continue
+		}
+
+		if m := sectionRe.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+
+		switch section {
+		case "Notes":
+			if m := hashCommentRe.FindStringSubmatch(line); m != nil {
+				text := strings.TrimSpace(strings.TrimPrefix(hashCommentRe.ReplaceAllString(line, ""), "-"))
+				entries = append(entries, BlameEntry{
+					Section: "Notes",
+					Key:     m[1],
+					Text:    text,
+					Author:  bl.Author,
+					Date:    bl.Date,
+				})
+			}
+
+		case "Todo":
+			if m := todoItemRe.FindStringSubmatch(line); m != nil {
+				hash := ""
+				if m[3] != "" {
+					if hm := hashCommentRe.FindStringSubmatch(m[3]); hm != nil {
+						hash = hm[1]
+					}
+				}
+				status := "open"
+				if m[1] == "x" {
+					status = "done"
+				}
+				entries = append(entries, BlameEntry{
+					Section: "Todo",
+					Key:     hash,
+					Status:  status,
+					Text:    m[2],
+					Author:  bl.Author,
+					Date:    bl.Date,
+				})
+			}
+
+		case "Log":
+			if m := logHeadingRe.FindStringSubmatch(line); m != nil {
+				logDate = m[1]
+				continue
+			}
+			if m := logEntryRe.FindStringSubmatch(line); m != nil {
+				entries = append(entries, BlameEntry{
+					Section: "Log",
+					Key:     strings.TrimSpace(logDate + " " + m[1]),
+					Text:    m[2],
+					Author:  bl.Author,
+					Date:    bl.Date,
+				})
+			}
+		}
+	}
+
+	return entries, nil
+}
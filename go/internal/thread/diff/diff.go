@@ -0,0 +1,187 @@
+// Package diff classifies the difference between two revisions of a
+// thread's ast.Document into the high-level operations that produced it -
+// "added note <hash>", "checked off todo <hash>", "appended log entry for
+// YYYY-MM-DD" - instead of a raw line-level diff, so `threads history` and
+// `threads blame` can report what happened rather than what text changed.
+package diff
+
+import "git.zib.de/cspiegel/threads/internal/thread/ast"
+
+// Op identifies the kind of change a Change describes.
+type Op string
+
+const (
+	OpAddNote     Op = "add_note"
+	OpRemoveNote  Op = "remove_note"
+	OpEditNote    Op = "edit_note"
+	OpAddTodo     Op = "add_todo"
+	OpRemoveTodo  Op = "remove_todo"
+	OpEditTodo    Op = "edit_todo"
+	OpCheckTodo   Op = "check_todo"
+	OpUncheckTodo Op = "uncheck_todo"
+	OpMoveItem    Op = "move_item"
+	OpLogEntry    Op = "log_entry"
+)
+
+// Change is one high-level operation Diff reconstructs between two
+// Documents.
+type Change struct {
+	Op      Op
+	Section string // section the item lives in after the change; for OpMoveItem, its destination
+	From    string // source section, set only for OpMoveItem
+	Hash    string
+	Text    string
+	Date    string // "YYYY-MM-DD", set only for OpLogEntry
+}
+
+// item is one Note or TodoItem, flattened out of a Document's sections for
+// comparison.
+type item struct {
+	section string
+	hash    string
+	text    string
+	checked bool
+	isTodo  bool
+}
+
+func flattenItems(doc *ast.Document) []item {
+	var items []item
+	for _, sec := range doc.Sections {
+		for _, b := range sec.Blocks {
+			switch v := b.(type) {
+			case *ast.Note:
+				items = append(items, item{section: sec.Name, hash: v.Hash, text: v.Text})
+			case *ast.TodoItem:
+				items = append(items, item{section: sec.Name, hash: v.Hash, text: v.Text, checked: v.Checked, isTodo: true})
+			}
+		}
+	}
+	return items
+}
+
+// Diff classifies the changes needed to turn a into b: items added,
+// removed, or edited; Todo items checked or unchecked; an item
+// moved from one section to another; and new Log entries. Items are
+// matched primarily by hash, but an item's hash is derived from its
+// (section, text) pair (see thread.NewItemID), so a plain move to a
+// different section changes the hash; items left unmatched by hash are
+// paired up a second time by identical text across sections to recognize
+// moves instead of reporting an unrelated remove+add.
+func Diff(a, b *ast.Document) []Change {
+	aItems := flattenItems(a)
+	bItems := flattenItems(b)
+
+	aByHash := make(map[string]item, len(aItems))
+	for _, it := range aItems {
+		aByHash[it.hash] = it
+	}
+	bByHash := make(map[string]item, len(bItems))
+	for _, it := range bItems {
+		bByHash[it.hash] = it
+	}
+
+	var changes []Change
+	var unmatchedRemoved, unmatchedAdded []item
+
+	for _, it := range aItems {
+		if _, ok := bByHash[it.hash]; !ok {
+			unmatchedRemoved = append(unmatchedRemoved, it)
+		}
+	}
+
+	for _, it := range bItems {
+		old, ok := aByHash[it.hash]
+		if !ok {
+			unmatchedAdded = append(unmatchedAdded, it)
+			continue
+		}
+		if old.text != it.text {
+			op := OpEditNote
+			if it.isTodo {
+				op = OpEditTodo
+			}
+			changes = append(changes, Change{Op: op, Section: it.section, Hash: it.hash, Text: it.text})
+		}
+		if it.isTodo && old.checked != it.checked {
+			op := OpUncheckTodo
+			if it.checked {
+				op = OpCheckTodo
+			}
+			changes = append(changes, Change{Op: op, Section: it.section, Hash: it.hash, Text: it.text})
+		}
+	}
+
+	// Pair up same-text removals/additions across different sections as a
+	// move rather than an unrelated remove+add.
+	usedAdded := make([]bool, len(unmatchedAdded))
+	for _, removed := range unmatchedRemoved {
+		moved := false
+		for i, added := range unmatchedAdded {
+			if usedAdded[i] || added.section == removed.section || added.text != removed.text {
+				continue
+			}
+			changes = append(changes, Change{Op: OpMoveItem, From: removed.section, Section: added.section, Hash: added.hash, Text: added.text})
+			usedAdded[i] = true
+			moved = true
+			break
+		}
+		if !moved {
+			op := OpRemoveNote
+			if removed.isTodo {
+				op = OpRemoveTodo
+			}
+			changes = append(changes, Change{Op: op, Section: removed.section, Hash: removed.hash, Text: removed.text})
+		}
+	}
+	for i, added := range unmatchedAdded {
+		if usedAdded[i] {
+			continue
+		}
+		op := OpAddNote
+		if added.isTodo {
+			op = OpAddTodo
+		}
+		changes = append(changes, Change{Op: op, Section: added.section, Hash: added.hash, Text: added.text})
+	}
+
+	changes = append(changes, logChanges(a, b)...)
+
+	return changes
+}
+
+// logChanges reports entries present under a day heading in b but not in
+// a, matched by exact text since Log entries carry no hash.
+func logChanges(a, b *ast.Document) []Change {
+	seen := make(map[string]map[string]bool) // date -> text -> true
+	for _, sec := range a.Sections {
+		for _, blk := range sec.Blocks {
+			day, ok := blk.(*ast.LogDay)
+			if !ok {
+				continue
+			}
+			if seen[day.Date] == nil {
+				seen[day.Date] = make(map[string]bool)
+			}
+			for _, e := range day.Entries {
+				seen[day.Date][e.Text] = true
+			}
+		}
+	}
+
+	var changes []Change
+	for _, sec := range b.Sections {
+		for _, blk := range sec.Blocks {
+			day, ok := blk.(*ast.LogDay)
+			if !ok {
+				continue
+			}
+			for _, e := range day.Entries {
+				if seen[day.Date][e.Text] {
+					continue
+				}
+				changes = append(changes, Change{Op: OpLogEntry, Section: sec.Name, Text: e.Text, Date: day.Date})
+			}
+		}
+	}
+	return changes
+}
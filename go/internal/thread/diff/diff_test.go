@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"testing"
+
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
+)
+
+func parse(t *testing.T, content string) *ast.Document {
+	t.Helper()
+	doc, err := ast.Parse(content)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return doc
+}
+
+func findChange(changes []Change, op Op) (Change, bool) {
+	for _, c := range changes {
+		if c.Op == op {
+			return c, true
+		}
+	}
+	return Change{}, false
+}
+
+func TestDiffDetectsAddedNote(t *testing.T) {
+	a := parse(t, "## Notes\n\n- Buy milk  <!-- aaaa -->\n")
+	b := parse(t, "## Notes\n\n- Buy milk  <!-- aaaa -->\n- Call bob  <!-- bbbb -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpAddNote)
+	if !ok {
+		t.Fatalf("Diff() = %+v, want an OpAddNote", changes)
+	}
+	if c.Hash != "bbbb" || c.Text != "Call bob" || c.Section != "Notes" {
+		t.Errorf("OpAddNote change = %+v, want hash bbbb, text %q, section Notes", c, "Call bob")
+	}
+}
+
+func TestDiffDetectsRemovedNote(t *testing.T) {
+	a := parse(t, "## Notes\n\n- Buy milk  <!-- aaaa -->\n- Call bob  <!-- bbbb -->\n")
+	b := parse(t, "## Notes\n\n- Buy milk  <!-- aaaa -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpRemoveNote)
+	if !ok || c.Hash != "bbbb" {
+		t.Errorf("Diff() = %+v, want an OpRemoveNote for bbbb", changes)
+	}
+}
+
+func TestDiffDetectsEditedNote(t *testing.T) {
+	a := parse(t, "## Notes\n\n- Buy milk  <!-- aaaa -->\n")
+	b := parse(t, "## Notes\n\n- Buy oat milk  <!-- aaaa -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpEditNote)
+	if !ok || c.Hash != "aaaa" || c.Text != "Buy oat milk" {
+		t.Errorf("Diff() = %+v, want an OpEditNote for aaaa with new text", changes)
+	}
+}
+
+func TestDiffDetectsCheckedTodo(t *testing.T) {
+	a := parse(t, "## Todo\n\n- [ ] Write report  <!-- cccc -->\n")
+	b := parse(t, "## Todo\n\n- [x] Write report  <!-- cccc -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpCheckTodo)
+	if !ok || c.Hash != "cccc" {
+		t.Errorf("Diff() = %+v, want an OpCheckTodo for cccc", changes)
+	}
+}
+
+func TestDiffDetectsUncheckedTodo(t *testing.T) {
+	a := parse(t, "## Todo\n\n- [x] Write report  <!-- cccc -->\n")
+	b := parse(t, "## Todo\n\n- [ ] Write report  <!-- cccc -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpUncheckTodo)
+	if !ok || c.Hash != "cccc" {
+		t.Errorf("Diff() = %+v, want an OpUncheckTodo for cccc", changes)
+	}
+}
+
+func TestDiffDetectsMoveAcrossSections(t *testing.T) {
+	a := parse(t, "## Notes\n\n- Follow up with team  <!-- dddd -->\n\n## Todo\n\n")
+	b := parse(t, "## Notes\n\n## Todo\n\n- [ ] Follow up with team  <!-- eeee -->\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpMoveItem)
+	if !ok {
+		t.Fatalf("Diff() = %+v, want an OpMoveItem", changes)
+	}
+	if c.From != "Notes" || c.Section != "Todo" || c.Text != "Follow up with team" {
+		t.Errorf("OpMoveItem change = %+v, want From Notes, Section Todo, matching text", c)
+	}
+
+	// A cross-section move must not also be reported as an unrelated
+	// remove+add.
+	if _, ok := findChange(changes, OpRemoveNote); ok {
+		t.Errorf("Diff() reported a spurious OpRemoveNote alongside the move: %+v", changes)
+	}
+	if _, ok := findChange(changes, OpAddTodo); ok {
+		t.Errorf("Diff() reported a spurious OpAddTodo alongside the move: %+v", changes)
+	}
+}
+
+func TestDiffDetectsNewLogEntry(t *testing.T) {
+	a := parse(t, "## Log\n\n### 2026-07-20\n\n- **09:00** Created thread.\n")
+	b := parse(t, "## Log\n\n### 2026-07-20\n\n- **10:00** Investigated root cause.\n- **09:00** Created thread.\n")
+
+	changes := Diff(a, b)
+	c, ok := findChange(changes, OpLogEntry)
+	if !ok || c.Date != "2026-07-20" || c.Text != "Investigated root cause." {
+		t.Errorf("Diff() = %+v, want an OpLogEntry for the new 2026-07-20 entry", changes)
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	content := "## Notes\n\n- Buy milk  <!-- aaaa -->\n"
+	a := parse(t, content)
+	b := parse(t, content)
+
+	if changes := Diff(a, b); len(changes) != 0 {
+		t.Errorf("Diff() of identical documents = %+v, want none", changes)
+	}
+}
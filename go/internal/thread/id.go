@@ -0,0 +1,60 @@
+package thread
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// minHashLen is the shortest prefix an ItemID ever hands out, matching the
+// 4-hex-char tags thread files have always used. AddNote and AddTodoItem
+// grow past it only when a shorter prefix would collide with another item
+// already in the workspace (see Index).
+const minHashLen = 4
+
+// ItemID is a content-addressed identifier for a Note or TodoItem: a
+// SHA-256 digest over its section and normalized text, the same way a git
+// object's ID is a digest over its content rather than an assigned number.
+// Re-running the same command against the same text always yields the
+// same ID, which the old time-seeded random hash never could.
+type ItemID struct {
+	full  string
+	short int
+}
+
+// NewItemID computes the content-addressed ID for an item's section (e.g.
+// "Notes", "Todo") and text.
+func NewItemID(section, text string) ItemID {
+	sum := sha256.Sum256([]byte(section + "\x00" + normalizeItemText(text)))
+	return ItemID{full: hex.EncodeToString(sum[:]), short: minHashLen}
+}
+
+// Full returns the complete 64-character hex digest.
+func (id ItemID) Full() string {
+	return id.full
+}
+
+// Short returns the prefix currently in use: minHashLen characters unless
+// WithShortLen has grown it.
+func (id ItemID) Short() string {
+	return id.full[:id.short]
+}
+
+// WithShortLen returns a copy of id whose Short() is n characters long,
+// clamped to [minHashLen, len(Full())].
+func (id ItemID) WithShortLen(n int) ItemID {
+	if n < minHashLen {
+		n = minHashLen
+	}
+	if n > len(id.full) {
+		n = len(id.full)
+	}
+	id.short = n
+	return id
+}
+
+// normalizeItemText collapses whitespace so cosmetic differences (trailing
+// spaces, a tab instead of a space) don't change an item's identity.
+func normalizeItemText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
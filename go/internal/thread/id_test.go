@@ -0,0 +1,75 @@
+package thread
+
+import "testing"
+
+func TestNewItemIDDeterministic(t *testing.T) {
+	a := NewItemID("Notes", "Buy milk")
+	b := NewItemID("Notes", "Buy milk")
+	if a.Full() != b.Full() {
+		t.Errorf("NewItemID not deterministic: %q != %q", a.Full(), b.Full())
+	}
+	if len(a.Full()) != 64 {
+		t.Errorf("Full() length = %d, want 64", len(a.Full()))
+	}
+}
+
+func TestNewItemIDDiffersBySectionAndText(t *testing.T) {
+	base := NewItemID("Notes", "Buy milk")
+	tests := []struct {
+		name    string
+		section string
+		text    string
+	}{
+		{"different text", "Notes", "Buy eggs"},
+		{"different section", "Todo", "Buy milk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := NewItemID(tt.section, tt.text)
+			if other.Full() == base.Full() {
+				t.Errorf("NewItemID(%q, %q) collided with NewItemID(\"Notes\", \"Buy milk\")", tt.section, tt.text)
+			}
+		})
+	}
+}
+
+func TestNewItemIDNormalizesWhitespace(t *testing.T) {
+	a := NewItemID("Notes", "Buy   milk\nand eggs")
+	b := NewItemID("Notes", "Buy milk and eggs")
+	if a.Full() != b.Full() {
+		t.Errorf("whitespace differences should normalize: %q != %q", a.Full(), b.Full())
+	}
+}
+
+func TestItemIDShort(t *testing.T) {
+	id := NewItemID("Notes", "Buy milk")
+	if got := id.Short(); len(got) != minHashLen || got != id.Full()[:minHashLen] {
+		t.Errorf("Short() = %q, want first %d chars of %q", got, minHashLen, id.Full())
+	}
+
+	grown := id.WithShortLen(8)
+	if got := grown.Short(); got != id.Full()[:8] {
+		t.Errorf("WithShortLen(8).Short() = %q, want %q", got, id.Full()[:8])
+	}
+}
+
+func TestItemIDWithShortLenClampsToRange(t *testing.T) {
+	id := NewItemID("Notes", "Buy milk")
+
+	if got := id.WithShortLen(1).Short(); got != id.Full()[:minHashLen] {
+		t.Errorf("WithShortLen(1) should clamp up to minHashLen, got %q", got)
+	}
+
+	if got := id.WithShortLen(1000).Short(); got != id.Full() {
+		t.Errorf("WithShortLen(1000) should clamp down to Full(), got %q", got)
+	}
+}
+
+func TestGenerateHashMatchesItemID(t *testing.T) {
+	got := GenerateHash("Notes", "Buy milk")
+	want := NewItemID("Notes", "Buy milk").Short()
+	if got != want {
+		t.Errorf("GenerateHash(%q, %q) = %q, want %q", "Notes", "Buy milk", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package thread
+
+import (
+	"os"
+
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
+)
+
+// Index is the set of every Note's and TodoItem's full content-addressed
+// hash across a set of thread files, built once per command so AddNote and
+// AddTodoItem can pick the shortest prefix that stays unique - the same
+// growth strategy git uses to decide how many characters an abbreviated
+// SHA needs. It doesn't help two items with identical text in the same
+// section tell apart, since those hash identically at any length; that
+// case is instead caught as an ambiguous-hash error when referenced later.
+type Index struct {
+	full []string
+}
+
+// BuildIndex scans paths (normally workspace.FindAllThreads's result) and
+// collects the full hash of every Note and TodoItem found. Files that fail
+// to read or parse are skipped rather than failing the whole scan.
+func BuildIndex(paths []string) *Index {
+	idx := &Index{}
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		doc, err := ast.Parse(string(content))
+		if err != nil {
+			continue
+		}
+		for _, sec := range doc.Sections {
+			for _, b := range sec.Blocks {
+				if text, ok := itemText(b); ok {
+					idx.full = append(idx.full, NewItemID(sec.Name, text).Full())
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// itemText returns a Note's or TodoItem's text and true, or "", false for
+// block types Index doesn't hash (LogDay, Raw).
+func itemText(b ast.Block) (string, bool) {
+	switch v := b.(type) {
+	case *ast.Note:
+		return v.Text, true
+	case *ast.TodoItem:
+		return v.Text, true
+	default:
+		return "", false
+	}
+}
+
+// ShortLen returns the shortest prefix length, starting at minHashLen, of
+// full that collides with no other hash currently in idx.
+func (idx *Index) ShortLen(full string) int {
+	for n := minHashLen; n < len(full); n++ {
+		if !idx.collidesAt(full, n) {
+			return n
+		}
+	}
+	return len(full)
+}
+
+// collidesAt reports whether any hash in idx other than full itself shares
+// full's first n characters. Only one occurrence of full is treated as
+// "itself" and skipped; a second occurrence with the same full hash is a
+// duplicate-content item and still counts as a collision, so ShortLen
+// returns the full length for it. The two items still end up with the
+// same hash (see Index).
+func (idx *Index) collidesAt(full string, n int) bool {
+	prefix := full[:n]
+	skippedSelf := false
+	for _, other := range idx.full {
+		if !skippedSelf && other == full {
+			skippedSelf = true
+			continue
+		}
+		if len(other) >= n && other[:n] == prefix {
+			return true
+		}
+	}
+	return false
+}
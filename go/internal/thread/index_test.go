@@ -0,0 +1,29 @@
+package thread
+
+import "testing"
+
+func TestIndexShortLenGrowsOnCollision(t *testing.T) {
+	a := NewItemID("Notes", "Buy milk").Full()
+	b := NewItemID("Notes", "Buy eggs").Full()
+
+	idx := &Index{full: []string{a, b}}
+
+	if got := idx.ShortLen(a); got != minHashLen {
+		t.Fatalf("ShortLen with no collision = %d, want %d", got, minHashLen)
+	}
+
+	// Force a collision at minHashLen by truncating b to share a's prefix.
+	idx.full = []string{a, a[:minHashLen] + b[minHashLen:]}
+	if got := idx.ShortLen(a); got <= minHashLen {
+		t.Errorf("ShortLen should grow past a collision at minHashLen, got %d", got)
+	}
+}
+
+func TestIndexShortLenDuplicateContentFallsBackToFull(t *testing.T) {
+	a := NewItemID("Notes", "Buy milk").Full()
+	idx := &Index{full: []string{a, a}}
+
+	if got := idx.ShortLen(a); got != len(a) {
+		t.Errorf("ShortLen for a true duplicate = %d, want len(full) = %d", got, len(a))
+	}
+}
@@ -0,0 +1,381 @@
+package thread
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// statusRank implements the status lattice used to merge concurrent status
+// changes: resolved > blocked > active > idea. A status not found in the
+// lattice ranks below everything so an unrecognized value never wins over
+// a known one.
+var statusRank = map[string]int{
+	"resolved": 4,
+	"blocked":  3,
+	"active":   2,
+	"idea":     1,
+}
+
+// rankOf returns the lattice rank of a status, ignoring any "(reason)" suffix.
+func rankOf(status string) int {
+	return statusRank[BaseStatus(status)]
+}
+
+// MergeStatus resolves a concurrent status edit using the lattice
+// (resolved > blocked > active > idea); statuses outside the lattice are
+// treated as lower priority than any lattice member and broken by taking
+// theirs, mirroring "last writer wins" for genuinely unordered values.
+func MergeStatus(ours, theirs string) string {
+	if ours == theirs {
+		return ours
+	}
+	oursRank, theirsRank := rankOf(ours), rankOf(theirs)
+	if oursRank != theirsRank {
+		if oursRank > theirsRank {
+			return ours
+		}
+		return theirs
+	}
+	return theirs
+}
+
+// MergeFrontmatter merges two concurrently-edited Frontmatter values against
+// their common ancestor, field by field. Status follows the lattice in
+// MergeStatus; every other field goes through mergeField, so a change made
+// on one side only wins, and only when both sides changed a field does the
+// newer "updated" timestamp decide, falling back to "theirs" if neither
+// side set one.
+func MergeFrontmatter(base, ours, theirs Frontmatter) Frontmatter {
+	merged := base
+
+	merged.Status = MergeStatus(ours.Status, theirs.Status)
+
+	oursNewer := frontmatterNewer(ours.Updated, theirs.Updated)
+
+	merged.Name = mergeField(base.Name, ours.Name, theirs.Name, oursNewer)
+	merged.Desc = mergeField(base.Desc, ours.Desc, theirs.Desc, oursNewer)
+	merged.ID = mergeField(base.ID, ours.ID, theirs.ID, oursNewer)
+
+	if ours.Updated != "" || theirs.Updated != "" {
+		if oursNewer {
+			merged.Updated = ours.Updated
+		} else {
+			merged.Updated = theirs.Updated
+		}
+	}
+
+	return merged
+}
+
+// mergeField picks whichever side changed a field from base; if both
+// changed it to different values, the side with the newer "updated"
+// timestamp wins.
+func mergeField(base, ours, theirs string, oursNewer bool) string {
+	switch {
+	case ours == theirs:
+		return ours
+	case ours == base:
+		return theirs
+	case theirs == base:
+		return ours
+	case oursNewer:
+		return ours
+	default:
+		return theirs
+	}
+}
+
+// frontmatterNewer reports whether the "ours" RFC3339 timestamp is newer
+// than "theirs". Unparsable or empty timestamps lose.
+func frontmatterNewer(ours, theirs string) bool {
+	ot, oErr := time.Parse(time.RFC3339, ours)
+	tt, tErr := time.Parse(time.RFC3339, theirs)
+	if oErr != nil {
+		return false
+	}
+	if tErr != nil {
+		return true
+	}
+	return ot.After(tt)
+}
+
+// logEntryRe matches a single Log bullet line, e.g. "- **14:03** Resolved."
+var logEntryRe = regexp.MustCompile(`^- \*\*(\d{2}:\d{2})\*\* (.*)$`)
+
+// logHeadingRe matches a Log day heading, e.g. "### 2026-07-27".
+var logHeadingRe = regexp.MustCompile(`^### (\d{4}-\d{2}-\d{2})$`)
+
+// MergeLog unions two Log sections by "### YYYY-MM-DD" heading, merging
+// each day's timestamped bullets in time order and deduplicating identical
+// lines. This makes concurrent log appends on two branches a non-conflict:
+// both additions survive, interleaved by timestamp.
+func MergeLog(ours, theirs string) string {
+	days := map[string]map[string]bool{} // date -> set of "HH:MM text" lines
+	var order []string
+
+	collect := func(section string) {
+		currentDay := ""
+		for _, line := range strings.Split(section, "\n") {
+			if m := logHeadingRe.FindStringSubmatch(line); m != nil {
+				currentDay = m[1]
+				if _, ok := days[currentDay]; !ok {
+					days[currentDay] = map[string]bool{}
+					order = append(order, currentDay)
+				}
+				continue
+			}
+			if currentDay == "" {
+				continue
+			}
+			if m := logEntryRe.FindStringSubmatch(line); m != nil {
+				days[currentDay][m[1]+" "+m[2]] = true
+			}
+		}
+	}
+
+	collect(ours)
+	collect(theirs)
+
+	sort.Strings(order)
+
+	var sb strings.Builder
+	for i, day := range order {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("### " + day + "\n\n")
+
+		var entries []string
+		for e := range days[day] {
+			entries = append(entries, e)
+		}
+		sort.Strings(entries)
+		for _, e := range entries {
+			sp := strings.IndexByte(e, ' ')
+			sb.WriteString("- **" + e[:sp] + "**" + e[sp:] + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// Conflict describes one spot Merge could not resolve structurally: either
+// two sides edited the same hash-tagged item differently, or the Body
+// section diverged on both sides. The merged content still contains a
+// human-readable conflict block at this location; Conflict exists so
+// callers (threads merge-driver, tests) can report a summary or count.
+type Conflict struct {
+	Section string
+	Hash    string // empty for a Body conflict
+	Base    string
+	Ours    string
+	Theirs  string
+}
+
+// Merge performs threads' section-aware 3-way merge: Notes and Todo are
+// unified item-by-item using each line's hash comment (so concurrent
+// additions on both sides are commutative and never conflict), Log is
+// unioned by day/time via MergeLog, frontmatter follows MergeFrontmatter,
+// and Body falls back to a plain 3-way text merge. It's the structural
+// engine behind `threads merge-driver`; base may be empty (the thread is
+// new on both sides).
+func Merge(base, ours, theirs []byte) ([]byte, []Conflict, error) {
+	baseFM, err := ParseFrontmatterString(string(base))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing base frontmatter: %w", err)
+	}
+	oursFM, err := ParseFrontmatterString(string(ours))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing our frontmatter: %w", err)
+	}
+	theirsFM, err := ParseFrontmatterString(string(theirs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing their frontmatter: %w", err)
+	}
+	mergedFM := MergeFrontmatter(baseFM, oursFM, theirsFM)
+
+	var conflicts []Conflict
+
+	notes, c := mergeItemSection("Notes",
+		ExtractSection(string(base), "Notes"),
+		ExtractSection(string(ours), "Notes"),
+		ExtractSection(string(theirs), "Notes"))
+	conflicts = append(conflicts, c...)
+
+	todo, c := mergeItemSection("Todo",
+		ExtractSection(string(base), "Todo"),
+		ExtractSection(string(ours), "Todo"),
+		ExtractSection(string(theirs), "Todo"))
+	conflicts = append(conflicts, c...)
+
+	mergedLog := MergeLog(ExtractSection(string(ours), "Log"), ExtractSection(string(theirs), "Log"))
+
+	body, c := mergeBody(
+		ExtractSection(string(base), "Body"),
+		ExtractSection(string(ours), "Body"),
+		ExtractSection(string(theirs), "Body"))
+	conflicts = append(conflicts, c...)
+
+	fmBytes, err := yaml.Marshal(&mergedFM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rendering merged frontmatter: %w", err)
+	}
+
+	var out strings.Builder
+	out.WriteString("---\n")
+	out.Write(fmBytes)
+	out.WriteString("---\n\n")
+	out.WriteString("## Body\n\n" + body + "\n\n")
+	out.WriteString("## Notes\n\n" + notes + "\n\n")
+	out.WriteString("## Todo\n\n" + todo + "\n\n")
+	out.WriteString("## Log\n\n" + mergedLog)
+
+	return []byte(out.String()), conflicts, nil
+}
+
+// splitItemsByHash breaks a Notes/Todo section into its hash-tagged lines,
+// keyed by hash, preserving first-seen order. Lines without a recognizable
+// <!-- hash --> comment (stray prose, blank lines) are dropped: every item
+// this package creates carries one, so an untagged line only shows up on
+// hand-edited content, where there's nothing to key a structural merge on.
+func splitItemsByHash(section string) (order []string, byHash map[string]string) {
+	byHash = map[string]string{}
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			continue
+		}
+		m := hashCommentRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		hash := m[1]
+		if _, seen := byHash[hash]; !seen {
+			order = append(order, hash)
+		}
+		byHash[hash] = line
+	}
+	return order, byHash
+}
+
+// conflictBlock renders a structured, human-reviewable marker for a hash
+// whose item diverged on both sides, in the same <<<<<<</=======/>>>>>>>
+// vocabulary git itself uses for text conflicts.
+func conflictBlock(hash, base, ours, theirs string) string {
+	return fmt.Sprintf("<!-- CONFLICT %s -->\n<<<<<<< ours\n%s\n=======\n%s\n>>>>>>> theirs\n<!-- base: %q -->\n<!-- /CONFLICT -->",
+		hash, ours, theirs, base)
+}
+
+// mergeItemSection unifies a Notes/Todo section by hash: items added on
+// either side are kept (concurrent additions are commutative, never
+// conflicting), a deletion on one side and no change on the other is
+// honored, and anything both sides changed to different content becomes a
+// conflictBlock and is reported.
+func mergeItemSection(section, base, ours, theirs string) (string, []Conflict) {
+	baseOrder, baseByHash := splitItemsByHash(base)
+	oursOrder, oursByHash := splitItemsByHash(ours)
+	theirsOrder, theirsByHash := splitItemsByHash(theirs)
+
+	var conflicts []Conflict
+	var resultOrder []string
+	resultByHash := map[string]string{}
+	seen := map[string]bool{}
+
+	add := func(hash, line string) {
+		if !seen[hash] {
+			seen[hash] = true
+			resultOrder = append(resultOrder, hash)
+		}
+		resultByHash[hash] = line
+	}
+
+	// Items known at the common ancestor: kept, dropped, edited, or conflicting.
+	for _, hash := range baseOrder {
+		b := baseByHash[hash]
+		o, inOurs := oursByHash[hash]
+		t, inTheirs := theirsByHash[hash]
+
+		switch {
+		case !inOurs && !inTheirs:
+			// deleted on both sides
+		case !inOurs && inTheirs:
+			if t != b {
+				conflicts = append(conflicts, Conflict{Section: section, Hash: hash, Base: b, Ours: "", Theirs: t})
+				add(hash, conflictBlock(hash, b, "(deleted)", t))
+			}
+			// else: deleted on ours, unchanged on theirs - respect the deletion
+		case inOurs && !inTheirs:
+			if o != b {
+				conflicts = append(conflicts, Conflict{Section: section, Hash: hash, Base: b, Ours: o, Theirs: ""})
+				add(hash, conflictBlock(hash, b, o, "(deleted)"))
+			}
+			// else: deleted on theirs, unchanged on ours - respect the deletion
+		default:
+			switch {
+			case o == t, o == b:
+				add(hash, t)
+			case t == b:
+				add(hash, o)
+			default:
+				conflicts = append(conflicts, Conflict{Section: section, Hash: hash, Base: b, Ours: o, Theirs: t})
+				add(hash, conflictBlock(hash, b, o, t))
+			}
+		}
+	}
+
+	// New items: additions on one side always survive; the same hash added
+	// independently on both sides with different text is its own conflict.
+	for _, hash := range oursOrder {
+		if _, inBase := baseByHash[hash]; inBase {
+			continue
+		}
+		o := oursByHash[hash]
+		if t, inTheirs := theirsByHash[hash]; inTheirs {
+			if o == t {
+				add(hash, o)
+			} else {
+				conflicts = append(conflicts, Conflict{Section: section, Hash: hash, Ours: o, Theirs: t})
+				add(hash, conflictBlock(hash, "", o, t))
+			}
+			continue
+		}
+		add(hash, o)
+	}
+	for _, hash := range theirsOrder {
+		if _, inBase := baseByHash[hash]; inBase {
+			continue
+		}
+		if seen[hash] {
+			continue
+		}
+		add(hash, theirsByHash[hash])
+	}
+
+	var sb strings.Builder
+	for _, hash := range resultOrder {
+		sb.WriteString(resultByHash[hash])
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), conflicts
+}
+
+// mergeBody falls back to a plain 3-way text merge: whichever side alone
+// changed the Body from base wins, and both sides changing it to different
+// text produces a standard conflict-marker block.
+func mergeBody(base, ours, theirs string) (string, []Conflict) {
+	switch {
+	case ours == theirs:
+		return ours, nil
+	case ours == base:
+		return theirs, nil
+	case theirs == base:
+		return ours, nil
+	}
+	block := fmt.Sprintf("<<<<<<< ours\n%s\n=======\n%s\n>>>>>>> theirs", ours, theirs)
+	return block, []Conflict{{Section: "Body", Base: base, Ours: ours, Theirs: theirs}}
+}
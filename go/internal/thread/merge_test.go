@@ -0,0 +1,69 @@
+package thread
+
+import (
+	"strings"
+	"testing"
+)
+
+const mergeFM = `---
+id: abc123
+name: test
+desc: test thread
+status: active
+---
+`
+
+func makeMergeThread(notes, todo, log string) []byte {
+	return []byte(mergeFM + "\n## Body\n\nSome body text.\n\n## Notes\n\n" + notes + "\n\n## Todo\n\n" + todo + "\n\n## Log\n\n" + log)
+}
+
+func TestMergeUnionsConcurrentNotes(t *testing.T) {
+	base := makeMergeThread("- old note  <!-- 0000 -->", "", "")
+	ours := makeMergeThread("- old note  <!-- 0000 -->\n- my note  <!-- aaaa -->", "", "")
+	theirs := makeMergeThread("- old note  <!-- 0000 -->\n- their note  <!-- bbbb -->", "", "")
+
+	merged, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+	notes := ExtractSection(string(merged), "Notes")
+	for _, want := range []string{"0000", "aaaa", "bbbb"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("merged Notes missing hash %s:\n%s", want, notes)
+		}
+	}
+}
+
+func TestMergeReportsConflictOnSameHashEditedBothSides(t *testing.T) {
+	base := makeMergeThread("- buy milk  <!-- aaaa -->", "", "")
+	ours := makeMergeThread("- buy oat milk  <!-- aaaa -->", "", "")
+	theirs := makeMergeThread("- buy soy milk  <!-- aaaa -->", "", "")
+
+	_, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Hash != "aaaa" {
+		t.Fatalf("want one conflict on hash aaaa, got %v", conflicts)
+	}
+}
+
+func TestMergeRespectsOneSidedDeletion(t *testing.T) {
+	base := makeMergeThread("- stale note  <!-- aaaa -->", "", "")
+	ours := makeMergeThread("", "", "")
+	theirs := makeMergeThread("- stale note  <!-- aaaa -->", "", "")
+
+	merged, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %v", conflicts)
+	}
+	if strings.Contains(ExtractSection(string(merged), "Notes"), "aaaa") {
+		t.Errorf("deleted note should not reappear:\n%s", merged)
+	}
+}
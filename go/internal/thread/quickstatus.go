@@ -0,0 +1,51 @@
+package thread
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// QuickStatus reads only the YAML frontmatter of a thread file and returns
+// its status, without reading or parsing the markdown body. It exists for
+// status-only callers like `threads stats` that scan large numbers of
+// threads and don't need the rest of Parse's work.
+func QuickStatus(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || scanner.Text() != "---" {
+		return "", fmt.Errorf("missing frontmatter delimiter")
+	}
+
+	var yamlLines []string
+	closed := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			closed = true
+			break
+		}
+		yamlLines = append(yamlLines, line)
+	}
+	if !closed {
+		return "", fmt.Errorf("unclosed frontmatter")
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	var fm Frontmatter
+	if err := yaml.Unmarshal([]byte(strings.Join(yamlLines, "\n")), &fm); err != nil {
+		return "", err
+	}
+
+	return fm.Status, nil
+}
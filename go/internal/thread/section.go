@@ -1,22 +1,24 @@
 package thread
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
+
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
 )
 
 // sectionRe matches section headers like "## Body", "## Notes", etc.
 var sectionRe = regexp.MustCompile(`(?m)^## (\w+)`)
 
-// hashCommentRe matches hash comments like "<!-- abc1 -->"
-var hashCommentRe = regexp.MustCompile(`<!--\s*([a-f0-9]{4})\s*-->`)
+// hashCommentRe matches hash comments like "<!-- abc1 -->". Items are
+// tagged with a content-addressed hash (see ItemID) at least minHashLen
+// characters long, grown further only when a shorter prefix would collide
+// with another item, so this doesn't assume a fixed length.
+var hashCommentRe = regexp.MustCompile(`<!--\s*([a-f0-9]{4,})\s*-->`)
 
 // todoItemRe matches todo items like "- [ ] item" or "- [x] item"
-var todoItemRe = regexp.MustCompile(`^- \[([ x])\] (.+?)\s*(<!--\s*[a-f0-9]{4}\s*-->)?$`)
+var todoItemRe = regexp.MustCompile(`^- \[([ x])\] (.+?)\s*(<!--\s*[a-f0-9]{4,}\s*-->)?$`)
 
 // ExtractSection returns the content of a section (between ## Name and next ## or EOF)
 func ExtractSection(content, name string) string {
@@ -71,161 +73,116 @@ func EnsureSection(content, name, before string) string {
 	return content + fmt.Sprintf("\n## %s\n\n", name)
 }
 
-// GenerateHash creates a 4-character hash for an item
-func GenerateHash(text string) string {
-	data := fmt.Sprintf("%s%d", text, time.Now().UnixNano())
-	hash := md5.Sum([]byte(data))
-	return hex.EncodeToString(hash[:])[:4]
+// GenerateHash returns the default, minimum-length content-addressed hash
+// for an item in section with the given text (see ItemID): the same text
+// in the same section always produces the same hash.
+func GenerateHash(section, text string) string {
+	return NewItemID(section, text).Short()
 }
 
-// InsertLogEntry adds a timestamped entry to the Log section
+// InsertLogEntry adds a timestamped entry to the Log section. It parses
+// content through the ast package so the entry lands under today's heading
+// (creating the heading, or the whole Log section, if neither exists yet)
+// regardless of how the rest of the file is formatted; unparseable content
+// is returned unchanged.
 func InsertLogEntry(content, entry string) string {
-	today := time.Now().Format("2006-01-02")
-	timestamp := time.Now().Format("15:04")
-	bulletEntry := fmt.Sprintf("- **%s** %s", timestamp, entry)
-	heading := fmt.Sprintf("### %s", today)
-
-	// Check if today's heading exists
-	todayPattern := regexp.MustCompile(fmt.Sprintf(`(?m)^### %s`, regexp.QuoteMeta(today)))
-	if todayPattern.MatchString(content) {
-		// Insert after today's heading
-		pattern := fmt.Sprintf(`(?m)(^### %s\n)`, regexp.QuoteMeta(today))
-		re := regexp.MustCompile(pattern)
-		return re.ReplaceAllString(content, fmt.Sprintf("${1}\n%s\n", bulletEntry))
-	}
-
-	// Check if Log section exists
-	logPattern := regexp.MustCompile(`(?m)^## Log`)
-	if logPattern.MatchString(content) {
-		// Insert new heading after ## Log
-		return logPattern.ReplaceAllString(content, fmt.Sprintf("## Log\n\n%s\n\n%s", heading, bulletEntry))
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content
 	}
-
-	// No Log section - append one
-	return content + fmt.Sprintf("\n## Log\n\n%s\n\n%s\n", heading, bulletEntry)
+	doc.InsertLogEntry(entry)
+	return doc.Render()
 }
 
-// AddNote adds a note to the Notes section with a hash comment
-func AddNote(content, text string) (string, string) {
-	// Ensure Notes section exists
-	content = EnsureSection(content, "Notes", "Todo")
-
-	hash := GenerateHash(text)
-	noteEntry := fmt.Sprintf("- %s  <!-- %s -->", text, hash)
-
-	// Insert at top of Notes section
-	pattern := regexp.MustCompile(`(?m)(^## Notes\n)`)
-	newContent := pattern.ReplaceAllString(content, fmt.Sprintf("${1}\n%s\n", noteEntry))
-
-	return newContent, hash
+// AddNote adds a note to the Notes section with a content-addressed hash
+// comment, creating the section if needed. It parses content through the
+// ast package; unparseable content is returned unchanged with a zero-value
+// hash. idx, if non-nil, grows the hash past its default minHashLen
+// characters when that would collide with another item already in idx
+// (see BuildIndex); pass nil to skip that check (e.g. in tests).
+func AddNote(content, text string, idx *Index) (string, string) {
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content, ""
+	}
+	hash := doc.AddNote(text)
+	hash = growHash(doc, "Notes", hash, text, idx)
+	return doc.Render(), hash
 }
 
-// RemoveByHash removes a line containing the specified hash comment from a section
+// RemoveByHash removes the note or todo item tagged with hash from section.
+// It parses content through the ast package and returns content unchanged
+// alongside the error if hash isn't found or content doesn't parse.
 func RemoveByHash(content, section, hash string) (string, error) {
-	lines := strings.Split(content, "\n")
-	inSection := false
-	hashPattern := fmt.Sprintf("<!-- %s", hash)
-	found := false
-
-	var result []string
-	for _, line := range lines {
-		if strings.HasPrefix(line, "## "+section) {
-			inSection = true
-		} else if strings.HasPrefix(line, "## ") {
-			inSection = false
-		}
-
-		if inSection && strings.Contains(line, hashPattern) && !found {
-			found = true
-			continue // skip this line
-		}
-		result = append(result, line)
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content, err
 	}
-
-	if !found {
-		return content, fmt.Errorf("no item with hash '%s' found", hash)
+	if err := doc.RemoveByHash(section, hash); err != nil {
+		return content, err
 	}
-
-	return strings.Join(result, "\n"), nil
+	return doc.Render(), nil
 }
 
-// EditByHash replaces the text of an item by hash
+// EditByHash replaces the text of the note or todo item tagged with hash in
+// section. It parses content through the ast package and returns content
+// unchanged alongside the error if hash isn't found or content doesn't parse.
 func EditByHash(content, section, hash, newText string) (string, error) {
-	lines := strings.Split(content, "\n")
-	inSection := false
-	hashPattern := fmt.Sprintf("<!-- %s", hash)
-	found := false
-
-	var result []string
-	for _, line := range lines {
-		if strings.HasPrefix(line, "## "+section) {
-			inSection = true
-		} else if strings.HasPrefix(line, "## ") {
-			inSection = false
-		}
-
-		if inSection && strings.Contains(line, hashPattern) && !found {
-			found = true
-			// Extract hash from line and rebuild
-			match := hashCommentRe.FindStringSubmatch(line)
-			if len(match) > 1 {
-				result = append(result, fmt.Sprintf("- %s  <!-- %s -->", newText, match[1]))
-				continue
-			}
-		}
-		result = append(result, line)
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content, err
 	}
-
-	if !found {
-		return content, fmt.Errorf("no item with hash '%s' found", hash)
+	if err := doc.EditByHash(section, hash, newText); err != nil {
+		return content, err
 	}
-
-	return strings.Join(result, "\n"), nil
+	return doc.Render(), nil
 }
 
-// AddTodoItem adds a checkbox item to the Todo section
-func AddTodoItem(content, text string) (string, string) {
-	hash := GenerateHash(text)
-	todoEntry := fmt.Sprintf("- [ ] %s  <!-- %s -->", text, hash)
-
-	// Insert at top of Todo section
-	pattern := regexp.MustCompile(`(?m)(^## Todo\n)`)
-	newContent := pattern.ReplaceAllString(content, fmt.Sprintf("${1}\n%s\n", todoEntry))
+// AddTodoItem adds an unchecked checkbox item to the Todo section with a
+// content-addressed hash comment, creating the section if needed. It parses
+// content through the ast package; unparseable content is returned
+// unchanged with a zero-value hash. idx behaves as in AddNote.
+func AddTodoItem(content, text string, idx *Index) (string, string) {
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content, ""
+	}
+	hash := doc.AddTodoItem(text)
+	hash = growHash(doc, "Todo", hash, text, idx)
+	return doc.Render(), hash
+}
 
-	return newContent, hash
+// growHash extends hash to the shortest prefix idx reports unique for
+// (section, text), re-tagging the item in doc if a longer prefix is
+// needed. idx == nil, or a doc that no longer parses as expected, leaves
+// hash as generated.
+func growHash(doc *ast.Document, section, hash, text string, idx *Index) string {
+	if idx == nil {
+		return hash
+	}
+	full := NewItemID(section, text).Full()
+	n := idx.ShortLen(full)
+	if n <= len(hash) {
+		return hash
+	}
+	if err := doc.GrowItemHash(section, hash, n); err != nil {
+		return hash
+	}
+	return full[:n]
 }
 
-// SetTodoChecked sets a todo item's checked state by hash
+// SetTodoChecked sets a todo item's checked state by hash. It parses content
+// through the ast package and returns content unchanged alongside the error
+// if hash isn't found or content doesn't parse.
 func SetTodoChecked(content, hash string, checked bool) (string, error) {
-	lines := strings.Split(content, "\n")
-	inTodo := false
-	hashPattern := fmt.Sprintf("<!-- %s", hash)
-	found := false
-
-	var result []string
-	for _, line := range lines {
-		if strings.HasPrefix(line, "## Todo") {
-			inTodo = true
-		} else if strings.HasPrefix(line, "## ") {
-			inTodo = false
-		}
-
-		if inTodo && strings.Contains(line, hashPattern) && !found {
-			found = true
-			if checked {
-				line = strings.Replace(line, "- [ ]", "- [x]", 1)
-			} else {
-				line = strings.Replace(line, "- [x]", "- [ ]", 1)
-			}
-		}
-		result = append(result, line)
+	doc, err := ast.Parse(content)
+	if err != nil {
+		return content, err
 	}
-
-	if !found {
-		return content, fmt.Errorf("no item with hash '%s' found", hash)
+	if err := doc.SetTodoChecked(hash, checked); err != nil {
+		return content, err
 	}
-
-	return strings.Join(result, "\n"), nil
+	return doc.Render(), nil
 }
 
 // CountMatchingItems counts items matching a hash prefix in a section
@@ -0,0 +1,153 @@
+package thread
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// cacheEntry is one (mtime, status) tuple in a StatusCache.
+type cacheEntry struct {
+	ModTime int64  `json:"mtime"`
+	Status  string `json:"status"`
+}
+
+// StatusCache memoizes QuickStatus results by file path and mtime, so a
+// repeated scan of an unchanged thread file skips re-reading it entirely.
+// It's persisted as JSON at $GIT_DIR/threads-cache.json.
+type StatusCache struct {
+	cachePath string
+	entries   map[string]cacheEntry
+	mu        sync.Mutex
+	dirty     bool
+}
+
+// cacheFilePath returns the cache file location for a given git root.
+func cacheFilePath(gitRoot string) string {
+	return filepath.Join(gitRoot, ".git", "threads-cache.json")
+}
+
+// LoadStatusCache reads the on-disk cache for gitRoot, or starts an empty
+// one if none exists yet or the existing file can't be parsed.
+func LoadStatusCache(gitRoot string) *StatusCache {
+	c := &StatusCache{
+		cachePath: cacheFilePath(gitRoot),
+		entries:   make(map[string]cacheEntry),
+	}
+
+	data, err := os.ReadFile(c.cachePath)
+	if err != nil {
+		return c
+	}
+	_ = json.Unmarshal(data, &c.entries) // corrupt cache: fall back to empty
+
+	return c
+}
+
+// Save writes the cache to disk if anything changed since it was loaded.
+func (c *StatusCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.cachePath, data, 0644)
+}
+
+func (c *StatusCache) lookup(path string, modTime int64) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok || entry.ModTime != modTime {
+		return "", false
+	}
+	return entry.Status, true
+}
+
+func (c *StatusCache) store(path string, modTime int64, status string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[path] = cacheEntry{ModTime: modTime, Status: status}
+	c.dirty = true
+}
+
+// QuickStatusAll resolves the status of every path in paths, preferring
+// cache for files whose mtime hasn't changed since the last scan and
+// running QuickStatus over a worker pool sized by runtime.NumCPU() for the
+// rest. Paths that fail to stat or parse are omitted from the result.
+func QuickStatusAll(paths []string, cache *StatusCache) map[string]string {
+	results := make(map[string]string, len(paths))
+	var resultsMu sync.Mutex
+
+	workers := runtime.NumCPU()
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				status, ok := statusFor(path, cache)
+				if !ok {
+					continue
+				}
+				resultsMu.Lock()
+				results[path] = status
+				resultsMu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range paths {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// statusFor resolves a single path's status, preferring the cache.
+func statusFor(path string, cache *StatusCache) (string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	modTime := info.ModTime().UnixNano()
+
+	if cache != nil {
+		if status, ok := cache.lookup(path, modTime); ok {
+			return status, true
+		}
+	}
+
+	status, err := QuickStatus(path)
+	if err != nil {
+		return "", false
+	}
+
+	if cache != nil {
+		cache.store(path, modTime, status)
+	}
+
+	return status, true
+}
@@ -19,10 +19,11 @@ var (
 
 // Frontmatter represents the YAML frontmatter of a thread
 type Frontmatter struct {
-	ID     string `yaml:"id"`
-	Name   string `yaml:"name"`
-	Desc   string `yaml:"desc"`
-	Status string `yaml:"status"`
+	ID      string `yaml:"id"`
+	Name    string `yaml:"name"`
+	Desc    string `yaml:"desc"`
+	Status  string `yaml:"status"`
+	Updated string `yaml:"updated,omitempty"` // RFC3339; used to break merge ties, see thread.MergeFrontmatter
 }
 
 // Thread represents a parsed thread file
@@ -46,8 +47,11 @@ type TodoItem struct {
 	Checked bool
 }
 
-// idPrefixRe matches ID-prefixed filenames like "abc123-slug-name.md"
-var idPrefixRe = regexp.MustCompile(`^([0-9a-f]{6})-`)
+// idPrefixRe matches a filename's leading hex ID: the configurable,
+// even-length run of hex digits (6 by default, up to 40) before the first
+// "-". The hex class itself already stops the match at the first
+// non-hex rune, so this also naturally accepts the original 6-char IDs.
+var idPrefixRe = regexp.MustCompile(`^([0-9a-f]{6,40})-`)
 
 // Parse reads and parses a thread file
 func Parse(path string) (*Thread, error) {
@@ -75,34 +79,60 @@ func Parse(path string) (*Thread, error) {
 
 // parseFrontmatter extracts and parses YAML frontmatter
 func (t *Thread) parseFrontmatter() error {
-	content := t.Content
+	fm, bodyStart, err := parseFrontmatterString(t.Content)
+	if err != nil {
+		return err
+	}
+	t.Frontmatter = fm
+	t.BodyStart = bodyStart
+	return nil
+}
+
+// ParseFrontmatterString parses the YAML frontmatter out of raw thread
+// content, for callers (merge drivers, tests) that have content in memory
+// rather than a file on disk. An empty string returns a zero Frontmatter
+// and no error, matching how Merge treats a missing base version.
+func ParseFrontmatterString(content string) (Frontmatter, error) {
+	if content == "" {
+		return Frontmatter{}, nil
+	}
+	fm, _, err := parseFrontmatterString(content)
+	return fm, err
+}
+
+// parseFrontmatterString is the shared implementation behind
+// (*Thread).parseFrontmatter and ParseFrontmatterString.
+func parseFrontmatterString(content string) (Frontmatter, int, error) {
+	var fm Frontmatter
 
 	if !strings.HasPrefix(content, "---\n") {
-		return fmt.Errorf("missing frontmatter delimiter")
+		return fm, 0, fmt.Errorf("missing frontmatter delimiter")
 	}
 
 	// Find closing delimiter
 	end := strings.Index(content[4:], "\n---")
 	if end == -1 {
-		return fmt.Errorf("unclosed frontmatter")
+		return fm, 0, fmt.Errorf("unclosed frontmatter")
 	}
 
 	yamlContent := content[4 : 4+end]
-	t.BodyStart = 4 + end + 4 // skip opening ---, yaml, closing ---, and newline
+	bodyStart := 4 + end + 4 // skip opening ---, yaml, closing ---, and newline
 
-	if err := yaml.Unmarshal([]byte(yamlContent), &t.Frontmatter); err != nil {
-		return err
+	if err := yaml.Unmarshal([]byte(yamlContent), &fm); err != nil {
+		return fm, 0, err
 	}
 
-	return nil
+	return fm, bodyStart, nil
 }
 
-// ExtractIDFromPath extracts the 6-char hex ID from a filename
+// ExtractIDFromPath extracts the hex ID from a filename. The ID's length
+// is whatever the repo is configured for (6 chars unless widened via
+// workspace.Config), so only an even-length match is accepted.
 func ExtractIDFromPath(path string) string {
 	filename := filepath.Base(path)
 	filename = strings.TrimSuffix(filename, ".md")
 
-	if m := idPrefixRe.FindStringSubmatch(filename); len(m) > 1 {
+	if m := idPrefixRe.FindStringSubmatch(filename); len(m) > 1 && len(m[1])%2 == 0 {
 		return m[1]
 	}
 	return ""
@@ -113,12 +143,41 @@ func ExtractNameFromPath(path string) string {
 	filename := filepath.Base(path)
 	filename = strings.TrimSuffix(filename, ".md")
 
-	if m := idPrefixRe.FindStringSubmatch(filename); len(m) > 1 {
-		return filename[7:] // skip "abc123-"
+	if m := idPrefixRe.FindStringSubmatch(filename); len(m) > 1 && len(m[1])%2 == 0 {
+		return filename[len(m[1])+1:] // skip "<id>-"
 	}
 	return filename
 }
 
+// FrontmatterFieldLine returns the 1-based line number (within the whole
+// file) of field's key inside t's frontmatter block (e.g. "status",
+// "name"), or 0 if the field isn't present in the raw YAML. Callers that
+// need to point at a field even when it's entirely absent (validate's CI
+// annotations) should fall back to line 1, the opening "---".
+func (t *Thread) FrontmatterFieldLine(field string) int {
+	return frontmatterFieldLine(t.Content, field)
+}
+
+// frontmatterFieldLine is the shared implementation behind
+// (*Thread).FrontmatterFieldLine.
+func frontmatterFieldLine(content, field string) int {
+	if !strings.HasPrefix(content, "---\n") {
+		return 0
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return 0
+	}
+	yamlContent := content[4 : 4+end]
+	prefix := field + ":"
+	for i, line := range strings.Split(yamlContent, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), prefix) {
+			return i + 2 // +1 for 1-based, +1 for the opening "---" line
+		}
+	}
+	return 0
+}
+
 // ID returns the thread ID
 func (t *Thread) ID() string {
 	return t.Frontmatter.ID
@@ -188,6 +247,8 @@ func (t *Thread) SetFrontmatterField(field, value string) error {
 		t.Frontmatter.Desc = value
 	case "status":
 		t.Frontmatter.Status = value
+	case "updated":
+		t.Frontmatter.Updated = value
 	default:
 		return fmt.Errorf("unknown field: %s", field)
 	}
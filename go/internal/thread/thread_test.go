@@ -11,9 +11,10 @@ func TestExtractIDFromPath(t *testing.T) {
 		{"/path/to/abc123-my-thread.md", "abc123"},
 		{"deadbe-another-one.md", "deadbe"},
 		{"no-id-here.md", ""},
-		{"ABC123-uppercase.md", ""}, // only lowercase hex
-		{"ab123-too-short.md", ""},  // need 6 chars
-		{"abc1234-too-long.md", ""},
+		{"ABC123-uppercase.md", ""},                    // only lowercase hex
+		{"ab123-too-short.md", ""},                     // need 6 chars
+		{"abc1234-too-long.md", ""},                    // odd length, rejected
+		{"deadbeefcafe-widened-id.md", "deadbeefcafe"}, // 12-char ID (config-widened)
 	}
 
 	for _, tt := range tests {
@@ -33,6 +34,7 @@ func TestExtractNameFromPath(t *testing.T) {
 		{"/path/to/abc123-my-thread.md", "my-thread"},
 		{"abc123-multi-word-name.md", "multi-word-name"},
 		{"no-id-here.md", "no-id-here"},
+		{"deadbeefcafe-widened-id.md", "widened-id"},
 	}
 
 	for _, tt := range tests {
@@ -43,6 +45,31 @@ func TestExtractNameFromPath(t *testing.T) {
 	}
 }
 
+func TestFrontmatterFieldLine(t *testing.T) {
+	content := "---\nid: abc123\nname: Demo\nstatus: active\n---\n\nBody text.\n"
+
+	tests := []struct {
+		field string
+		want  int
+	}{
+		{"id", 2},
+		{"name", 3},
+		{"status", 4},
+		{"desc", 0}, // absent field
+	}
+
+	for _, tt := range tests {
+		got := frontmatterFieldLine(content, tt.field)
+		if got != tt.want {
+			t.Errorf("frontmatterFieldLine(%q) = %d, want %d", tt.field, got, tt.want)
+		}
+	}
+
+	if got := frontmatterFieldLine("no frontmatter here", "status"); got != 0 {
+		t.Errorf("frontmatterFieldLine without frontmatter = %d, want 0", got)
+	}
+}
+
 func TestBaseStatus(t *testing.T) {
 	tests := []struct {
 		status string
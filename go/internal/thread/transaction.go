@@ -0,0 +1,260 @@
+package thread
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread/ast"
+)
+
+// txOps counts how many of each kind of operation a Transaction has
+// applied, just enough for Summary to synthesize a commit message.
+type txOps struct {
+	addedNotes, removedNotes, editedNotes int
+	addedTodos, removedTodos, editedTodos int
+	checkedTodos, uncheckedTodos          int
+	logEntries                            int
+}
+
+// Transaction batches several mutations to one thread file into a single
+// parse and a single atomic write, instead of the package-level AddNote/RemoveByHash/...,
+// each of which re-parses and re-renders the whole file on its own. Begin,
+// apply operations, then Commit to write the result and land it in one git
+// commit.
+type Transaction struct {
+	path string
+	doc  *ast.Document
+	idx  *Index
+	ops  txOps
+	err  error
+}
+
+// Begin parses the thread file at path for a Transaction. idx, if non-nil,
+// is used the same way AddNote and AddTodoItem use it: to grow a new item's
+// hash past its default length when that would collide with another item
+// already in idx.
+func Begin(path string, idx *Index) (*Transaction, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ast.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &Transaction{path: path, doc: doc, idx: idx}, nil
+}
+
+// Err returns the first error any operation on t has hit, or nil. Once set,
+// every further operation is a no-op that returns it, and Commit refuses to
+// write - so a transaction either applies every operation or none of them.
+func (t *Transaction) Err() error {
+	return t.err
+}
+
+// AddNote prepends a note to the Notes section and returns its hash.
+func (t *Transaction) AddNote(text string) string {
+	if t.err != nil {
+		return ""
+	}
+	hash := t.doc.AddNote(text)
+	hash = growHash(t.doc, "Notes", hash, text, t.idx)
+	t.ops.addedNotes++
+	return hash
+}
+
+// AddTodo prepends an unchecked todo item to the Todo section and returns
+// its hash.
+func (t *Transaction) AddTodo(text string) string {
+	if t.err != nil {
+		return ""
+	}
+	hash := t.doc.AddTodoItem(text)
+	hash = growHash(t.doc, "Todo", hash, text, t.idx)
+	t.ops.addedTodos++
+	return hash
+}
+
+// RemoveNote removes the note matching hash.
+func (t *Transaction) RemoveNote(hash string) error {
+	return t.remove("Notes", hash, &t.ops.removedNotes)
+}
+
+// RemoveTodo removes the todo item matching hash.
+func (t *Transaction) RemoveTodo(hash string) error {
+	return t.remove("Todo", hash, &t.ops.removedTodos)
+}
+
+func (t *Transaction) remove(section, hash string, counter *int) error {
+	if t.err != nil {
+		return t.err
+	}
+	if err := t.doc.RemoveByHash(section, hash); err != nil {
+		t.err = err
+		return err
+	}
+	*counter++
+	return nil
+}
+
+// EditNote replaces the text of the note matching hash.
+func (t *Transaction) EditNote(hash, newText string) error {
+	return t.edit("Notes", hash, newText, &t.ops.editedNotes)
+}
+
+// EditTodo replaces the text of the todo item matching hash.
+func (t *Transaction) EditTodo(hash, newText string) error {
+	return t.edit("Todo", hash, newText, &t.ops.editedTodos)
+}
+
+func (t *Transaction) edit(section, hash, newText string, counter *int) error {
+	if t.err != nil {
+		return t.err
+	}
+	if err := t.doc.EditByHash(section, hash, newText); err != nil {
+		t.err = err
+		return err
+	}
+	*counter++
+	return nil
+}
+
+// CheckTodo marks the todo item matching hash as checked.
+func (t *Transaction) CheckTodo(hash string) error {
+	return t.setTodoChecked(hash, true)
+}
+
+// UncheckTodo marks the todo item matching hash as unchecked.
+func (t *Transaction) UncheckTodo(hash string) error {
+	return t.setTodoChecked(hash, false)
+}
+
+func (t *Transaction) setTodoChecked(hash string, checked bool) error {
+	if t.err != nil {
+		return t.err
+	}
+	if err := t.doc.SetTodoChecked(hash, checked); err != nil {
+		t.err = err
+		return err
+	}
+	if checked {
+		t.ops.checkedTodos++
+	} else {
+		t.ops.uncheckedTodos++
+	}
+	return nil
+}
+
+// AppendLog adds a timestamped entry to today's Log heading.
+func (t *Transaction) AppendLog(entry string) {
+	if t.err != nil {
+		return
+	}
+	t.doc.InsertLogEntry(entry)
+	t.ops.logEntries++
+}
+
+// Summary synthesizes a short commit message from every operation applied
+// so far, e.g. "threads: +1 note, +1 todo, check 1 todo on 'project-x'",
+// for Commit calls that don't supply their own message.
+func (t *Transaction) Summary() string {
+	var parts []string
+	add := func(n int, prefix, noun string) {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d %s", prefix, n, pluralize(n, noun)))
+		}
+	}
+	add(t.ops.addedNotes, "+", "note")
+	add(t.ops.removedNotes, "-", "note")
+	add(t.ops.editedNotes, "edit ", "note")
+	add(t.ops.addedTodos, "+", "todo")
+	add(t.ops.removedTodos, "-", "todo")
+	add(t.ops.editedTodos, "edit ", "todo")
+	add(t.ops.checkedTodos, "check ", "todo")
+	add(t.ops.uncheckedTodos, "uncheck ", "todo")
+	add(t.ops.logEntries, "+", "log entry")
+
+	name := ExtractNameFromPath(t.path)
+	if len(parts) == 0 {
+		return fmt.Sprintf("threads: update '%s'", name)
+	}
+
+	msg := "threads: "
+	for i, p := range parts {
+		if i > 0 {
+			msg += ", "
+		}
+		msg += p
+	}
+	return fmt.Sprintf("%s on '%s'", msg, name)
+}
+
+// pluralize returns noun unchanged for n == 1, otherwise its plural: a
+// trailing "y" becomes "ies" (entry -> entries), everything else just gets
+// an "s".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return noun[:len(noun)-1] + "ies"
+	}
+	return noun + "s"
+}
+
+// Commit writes the transaction's document to disk atomically - a temp file
+// in the same directory, renamed into place, so nothing ever observes a
+// half-written thread file - then stages and commits it in ws as a single
+// commit via the embedded go-git backend. opts.Message defaults to
+// Summary() if empty.
+func (t *Transaction) Commit(ws string, opts git.CommitOptions) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	if err := t.write(); err != nil {
+		return err
+	}
+
+	if opts.Message == "" {
+		opts.Message = t.Summary()
+	}
+
+	relPath, err := filepath.Rel(ws, t.path)
+	if err != nil {
+		relPath = t.path
+	}
+
+	return git.NewGoGitClient(ws).CommitWithOptions([]string{relPath}, opts)
+}
+
+// write renders the transaction's document and atomically replaces path
+// with it: written to a temp file in the same directory first, then
+// renamed into place, so a crash mid-write (or a concurrent reader) never
+// sees a partial file.
+func (t *Transaction) write() error {
+	tmp, err := os.CreateTemp(filepath.Dir(t.path), filepath.Base(t.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp file for %s: %w", t.path, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.WriteString(t.doc.Render()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("setting permissions on %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, t.path); err != nil {
+		return fmt.Errorf("renaming %s into place: %w", tmpPath, err)
+	}
+	return nil
+}
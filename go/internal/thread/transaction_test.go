@@ -0,0 +1,100 @@
+package thread
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTempThread(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "abc123-project-x.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing temp thread: %v", err)
+	}
+	return path
+}
+
+func TestTransactionAppliesOperationsInOnePass(t *testing.T) {
+	path := writeTempThread(t, "---\nid: abc123\nname: project-x\n---\n\n## Notes\n\n## Todo\n\n")
+
+	tx, err := Begin(path, nil)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	noteHash := tx.AddNote("Buy milk")
+	todoHash := tx.AddTodo("Write report")
+	if err := tx.CheckTodo(todoHash); err != nil {
+		t.Fatalf("CheckTodo: %v", err)
+	}
+	tx.AppendLog("Did some setup")
+
+	if tx.Err() != nil {
+		t.Fatalf("Err() = %v, want nil", tx.Err())
+	}
+	if noteHash == "" || todoHash == "" {
+		t.Fatalf("AddNote/AddTodo returned empty hash")
+	}
+
+	want := "threads: +1 note, +1 todo, check 1 todo, +1 log entry on 'project-x'"
+	if got := tx.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestTransactionAbortsOnFirstError(t *testing.T) {
+	path := writeTempThread(t, "---\nid: abc123\nname: project-x\n---\n\n## Notes\n\n")
+
+	tx, err := Begin(path, nil)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	tx.AddNote("Buy milk")
+	if err := tx.RemoveNote("nonexistent"); err == nil {
+		t.Fatalf("RemoveNote with an unknown hash should fail")
+	}
+	if tx.Err() == nil {
+		t.Fatalf("Err() = nil after a failed operation")
+	}
+
+	// Further operations, and Commit, should surface the same error rather
+	// than silently applying more of the transaction.
+	if err := tx.CheckTodo("whatever"); err != tx.Err() {
+		t.Errorf("operation after a failure = %v, want the stored error %v", err, tx.Err())
+	}
+}
+
+func TestTransactionWriteIsAtomic(t *testing.T) {
+	path := writeTempThread(t, "---\nid: abc123\nname: project-x\n---\n\n## Notes\n\n")
+
+	tx, err := Begin(path, nil)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	tx.AddNote("Buy milk")
+
+	if err := tx.write(); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != filepath.Base(path) {
+			t.Errorf("leftover file after write: %s", e.Name())
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(content), "Buy milk") {
+		t.Errorf("written file doesn't contain the added note: %s", content)
+	}
+}
@@ -0,0 +1,357 @@
+// Package tui implements the interactive `threads tui` browser: a
+// lazygit-style three-pane view over the scopes, threads, and content that
+// the one-shot CLI commands already operate on.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"git.zib.de/cspiegel/threads/internal/git"
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+// pane identifies which of the three columns has focus.
+type pane int
+
+const (
+	paneScopes pane = iota
+	paneThreads
+	paneDetail
+)
+
+// scopeEntry is one row of the left-hand directory tree of .threads/ scopes.
+type scopeEntry struct {
+	path string // git-root-relative, "." for root
+}
+
+// threadRow is one row of the middle thread list.
+type threadRow struct {
+	path   string
+	id     string
+	title  string
+	status string
+	age    time.Duration
+}
+
+// Model is the bubbletea model for `threads tui`.
+type Model struct {
+	gitRoot string
+
+	focus  pane
+	scopes []scopeEntry
+	scope  int
+
+	threads      []threadRow
+	threadCursor int
+	statusFilter string
+
+	detail    viewport.Model
+	width     int
+	height    int
+	statusBar string
+}
+
+// NewModel builds the initial TUI state for the given workspace.
+func NewModel(gitRoot string) (Model, error) {
+	m := Model{gitRoot: gitRoot, detail: viewport.New(0, 0)}
+	if err := m.reload(); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// reload rescans the workspace for scopes and threads, preserving cursor
+// position where possible.
+func (m *Model) reload() error {
+	all, err := workspace.FindAllThreads(m.gitRoot)
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	var scopes []scopeEntry
+	for _, p := range all {
+		rel := workspace.ParseThreadPath(m.gitRoot, p)
+		if !seen[rel] {
+			seen[rel] = true
+			scopes = append(scopes, scopeEntry{path: rel})
+		}
+	}
+	m.scopes = scopes
+	if m.scope >= len(m.scopes) {
+		m.scope = 0
+	}
+
+	return m.loadThreads()
+}
+
+// loadThreads populates the middle pane from the currently selected scope.
+func (m *Model) loadThreads() error {
+	all, err := workspace.FindAllThreads(m.gitRoot)
+	if err != nil {
+		return err
+	}
+
+	var rows []threadRow
+	for _, p := range all {
+		rel := workspace.ParseThreadPath(m.gitRoot, p)
+		if len(m.scopes) > 0 && rel != m.scopes[m.scope].path {
+			continue
+		}
+
+		t, err := thread.Parse(p)
+		if err != nil {
+			continue
+		}
+		status := t.BaseStatus()
+		if m.statusFilter != "" && status != m.statusFilter {
+			continue
+		}
+
+		age := time.Duration(0)
+		if info, statErr := os.Stat(p); statErr == nil {
+			age = time.Since(info.ModTime())
+		}
+
+		rows = append(rows, threadRow{
+			path:   p,
+			id:     t.ID(),
+			title:  t.Name(),
+			status: status,
+			age:    age,
+		})
+	}
+	m.threads = rows
+	if m.threadCursor >= len(m.threads) {
+		m.threadCursor = len(m.threads) - 1
+	}
+	if m.threadCursor < 0 {
+		m.threadCursor = 0
+	}
+	return m.refreshDetail()
+}
+
+// refreshDetail renders the selected thread's full markdown (Log included)
+// into the right-hand viewport.
+func (m *Model) refreshDetail() error {
+	if len(m.threads) == 0 {
+		m.detail.SetContent("(no threads)")
+		return nil
+	}
+	row := m.threads[m.threadCursor]
+	t, err := thread.Parse(row.path)
+	if err != nil {
+		m.detail.SetContent(fmt.Sprintf("error: %v", err))
+		return nil
+	}
+	m.detail.SetContent(t.Content)
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.detail.Width = msg.Width / 2
+		m.detail.Height = msg.Height - 3
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "tab":
+		m.focus = (m.focus + 1) % 3
+		return m, nil
+
+	case "/":
+		m.statusFilter = cycleStatusFilter(m.statusFilter)
+		_ = m.loadThreads()
+		return m, nil
+
+	case "up", "k":
+		m.moveCursor(-1)
+		_ = m.refreshDetail()
+		return m, nil
+
+	case "down", "j":
+		m.moveCursor(1)
+		_ = m.refreshDetail()
+		return m, nil
+
+	case "r":
+		if len(m.threads) > 0 {
+			_ = resolveThread(m.threads[m.threadCursor].path)
+			_ = m.loadThreads()
+			m.statusBar = "Resolved " + m.threads[min(m.threadCursor, len(m.threads)-1)].id
+		}
+		return m, nil
+
+	case "c":
+		if len(m.threads) > 0 {
+			row := m.threads[m.threadCursor]
+			relPath, _ := filepath.Rel(m.gitRoot, row.path)
+			msg := git.GenerateCommitMessage(m.gitRoot, []string{row.path})
+			if err := git.AutoCommit(m.gitRoot, row.path, msg); err != nil {
+				m.statusBar = "commit failed: " + err.Error()
+			} else {
+				m.statusBar = "Committed " + relPath
+			}
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m *Model) moveCursor(delta int) {
+	switch m.focus {
+	case paneScopes:
+		m.scope = clamp(m.scope+delta, 0, len(m.scopes)-1)
+		_ = m.loadThreads()
+	case paneThreads:
+		m.threadCursor = clamp(m.threadCursor+delta, 0, len(m.threads)-1)
+	}
+}
+
+func (m Model) View() string {
+	if m.width == 0 {
+		return "loading...\n"
+	}
+
+	scopesCol := m.renderScopes()
+	threadsCol := m.renderThreads()
+	detailCol := m.detail.View()
+
+	colWidth := m.width / 3
+	row := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(colWidth).Render(scopesCol),
+		lipgloss.NewStyle().Width(colWidth).Render(threadsCol),
+		lipgloss.NewStyle().Width(m.width-2*colWidth).Render(detailCol),
+	)
+
+	bar := m.statusBar
+	if bar == "" {
+		bar = m.statsSummary()
+	}
+
+	return row + "\n" + bar + "\n" +
+		"tab: switch pane  ↑/↓: move  r: resolve  c: commit  /: filter status  q: quit"
+}
+
+func (m Model) renderScopes() string {
+	var b strings.Builder
+	b.WriteString("SCOPES\n")
+	for i, s := range m.scopes {
+		marker := "  "
+		if i == m.scope {
+			marker = "▸ "
+		}
+		path := s.path
+		if path == "." {
+			path = "(root)"
+		}
+		fmt.Fprintf(&b, "%s%s\n", marker, path)
+	}
+	return b.String()
+}
+
+func (m Model) renderThreads() string {
+	var b strings.Builder
+	b.WriteString("THREADS\n")
+	for i, t := range m.threads {
+		marker := "  "
+		if i == m.threadCursor {
+			marker = "▸ "
+		}
+		fmt.Fprintf(&b, "%s%s %-10s %-6s %s\n", marker, t.id, t.status, formatAge(t.age), t.title)
+	}
+	return b.String()
+}
+
+// statsSummary replaces `threads stats` output with a status-bar tally.
+func (m Model) statsSummary() string {
+	counts := map[string]int{}
+	for _, t := range m.threads {
+		counts[t.status]++
+	}
+	var parts []string
+	for status, n := range counts {
+		parts = append(parts, fmt.Sprintf("%s:%d", status, n))
+	}
+	return strings.Join(parts, "  ")
+}
+
+func cycleStatusFilter(current string) string {
+	order := append([]string{""}, thread.AllStatuses...)
+	for i, s := range order {
+		if s == current {
+			return order[(i+1)%len(order)]
+		}
+	}
+	return ""
+}
+
+func resolveThread(path string) error {
+	t, err := thread.Parse(path)
+	if err != nil {
+		return err
+	}
+	if err := t.SetFrontmatterField("status", "resolved"); err != nil {
+		return err
+	}
+	t.Content = thread.InsertLogEntry(t.Content, "Resolved.")
+	return t.Write()
+}
+
+// formatAge renders a duration as a short "3d"/"4h"/"12m" label.
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if hi < lo {
+		return lo
+	}
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultStaleDays is how many days a thread may sit in an active status
+// before stale-in-progress fires, absent a stale_days override.
+const defaultStaleDays = 14
+
+// RulesConfig controls which rules run and at what severity.
+type RulesConfig struct {
+	// Enabled, if non-empty, restricts the ruleset to exactly these rule IDs.
+	Enabled []string `yaml:"enabled"`
+	// Disabled removes these rule IDs from the set Enabled would otherwise run.
+	Disabled []string `yaml:"disabled"`
+	// Severity overrides a rule's default severity, e.g. {stale-in-progress: warning}.
+	Severity map[string]string `yaml:"severity"`
+	// StaleDays configures RuleStaleInProgress; defaults to 14.
+	StaleDays int `yaml:"stale_days"`
+}
+
+// Config is a workspace's validation configuration, read from
+// gitRoot/.threads.yaml.
+type Config struct {
+	Rules RulesConfig `yaml:"rules"`
+}
+
+func configPath(gitRoot string) string {
+	return filepath.Join(gitRoot, ".threads.yaml")
+}
+
+// LoadConfig reads gitRoot's .threads.yaml, defaulting to the full
+// built-in ruleset at its default severities when the file is absent.
+func LoadConfig(gitRoot string) (Config, error) {
+	cfg := Config{Rules: RulesConfig{StaleDays: defaultStaleDays}}
+
+	data, err := os.ReadFile(configPath(gitRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading %s: %w", configPath(gitRoot), err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", configPath(gitRoot), err)
+	}
+	if cfg.Rules.StaleDays <= 0 {
+		cfg.Rules.StaleDays = defaultStaleDays
+	}
+
+	return cfg, nil
+}
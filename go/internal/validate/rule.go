@@ -0,0 +1,47 @@
+// Package validate implements threads' validation rules: a pluggable set
+// of named checks run over parsed threads, configured per-workspace via
+// .threads.yaml, modeled after linters like revive.
+package validate
+
+import "git.zib.de/cspiegel/threads/internal/thread"
+
+// Severity is how seriously an Issue should be treated. It determines both
+// the workflow command a CI renderer emits (::error vs ::warning) and
+// whether the overall validate run fails.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Issue is one finding from a Rule. Line is 1-based and refers to the
+// thread file as a whole; 0 means the rule couldn't attribute the issue
+// to a specific line.
+type Issue struct {
+	RuleID   string
+	Severity Severity
+	Message  string
+	Line     int
+}
+
+// Rule is a single named validation check run over one parsed thread at a
+// time. Rules that need the whole workspace's context (duplicate-title,
+// broken-cross-reference) are instead constructed once with that context
+// already baked in - see newDuplicateTitleRule and
+// newBrokenCrossReferenceRule.
+type Rule interface {
+	Name() string
+	Check(t *thread.Thread) []Issue
+}
+
+// frontmatterLineOrDefault looks up field's line within t's frontmatter,
+// falling back to line 1 (the opening "---") when the field is absent
+// entirely, so a "missing" issue still points somewhere in the file.
+func frontmatterLineOrDefault(t *thread.Thread, field string) int {
+	if line := t.FrontmatterFieldLine(field); line > 0 {
+		return line
+	}
+	return 1
+}
@@ -0,0 +1,252 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"git.zib.de/cspiegel/threads/internal/thread"
+	"git.zib.de/cspiegel/threads/internal/workspace"
+)
+
+// Rule IDs for the built-in ruleset. These are stable identifiers -
+// .threads.yaml's enabled/disabled/severity keys refer to them by name.
+const (
+	RuleMissingName          = "missing-name"
+	RuleMissingStatus        = "missing-status"
+	RuleInvalidStatus        = "invalid-status"
+	RuleStaleInProgress      = "stale-in-progress"
+	RuleDuplicateTitle       = "duplicate-title"
+	RuleFilenameMismatch     = "filename-mismatch"
+	RuleBrokenCrossReference = "broken-cross-reference"
+)
+
+// defaultSeverity is each built-in rule's severity before .threads.yaml's
+// severity overrides are applied.
+var defaultSeverity = map[string]Severity{
+	RuleMissingName:          SeverityError,
+	RuleMissingStatus:        SeverityError,
+	RuleInvalidStatus:        SeverityError,
+	RuleStaleInProgress:      SeverityWarning,
+	RuleDuplicateTitle:       SeverityWarning,
+	RuleFilenameMismatch:     SeverityWarning,
+	RuleBrokenCrossReference: SeverityError,
+}
+
+// RuleDescription is static metadata about a built-in rule, independent of
+// any particular thread's issues, for renderers (--format=sarif) that need
+// a rule catalog up front.
+type RuleDescription struct {
+	ID               string
+	ShortDescription string
+}
+
+// RuleDescriptions returns metadata for every built-in rule, in the same
+// order newBuiltinRules constructs them.
+func RuleDescriptions() []RuleDescription {
+	return []RuleDescription{
+		{RuleMissingName, "Thread is missing a name/title field"},
+		{RuleMissingStatus, "Thread is missing a status field"},
+		{RuleInvalidStatus, "Thread's status isn't one of the recognized values"},
+		{RuleStaleInProgress, "Thread has sat in an active status with no changes for too long"},
+		{RuleDuplicateTitle, "Thread's name is shared by another thread"},
+		{RuleFilenameMismatch, "Thread's filename slug no longer matches its name"},
+		{RuleBrokenCrossReference, "Thread references another thread ID that doesn't exist"},
+	}
+}
+
+// newBuiltinRules constructs the full default ruleset. duplicate-title and
+// broken-cross-reference need the whole workspace's threads to check
+// against, so they're built once up front rather than looking it up on
+// every Check call.
+func newBuiltinRules(cfg RulesConfig, threads []*thread.Thread) []Rule {
+	return []Rule{
+		missingNameRule{},
+		missingStatusRule{},
+		invalidStatusRule{},
+		staleInProgressRule{staleDays: cfg.StaleDays},
+		newDuplicateTitleRule(threads),
+		filenameMismatchRule{},
+		newBrokenCrossReferenceRule(threads),
+	}
+}
+
+type missingNameRule struct{}
+
+func (missingNameRule) Name() string { return RuleMissingName }
+
+func (missingNameRule) Check(t *thread.Thread) []Issue {
+	if t.Name() != "" {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleMissingName,
+		Message: "missing name/title field",
+		Line:    frontmatterLineOrDefault(t, "name"),
+	}}
+}
+
+type missingStatusRule struct{}
+
+func (missingStatusRule) Name() string { return RuleMissingStatus }
+
+func (missingStatusRule) Check(t *thread.Thread) []Issue {
+	if t.Status() != "" {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleMissingStatus,
+		Message: "missing status field",
+		Line:    frontmatterLineOrDefault(t, "status"),
+	}}
+}
+
+type invalidStatusRule struct{}
+
+func (invalidStatusRule) Name() string { return RuleInvalidStatus }
+
+func (invalidStatusRule) Check(t *thread.Thread) []Issue {
+	if t.Status() == "" || thread.IsValidStatus(t.Status()) {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleInvalidStatus,
+		Message: fmt.Sprintf("invalid status '%s'", thread.BaseStatus(t.Status())),
+		Line:    frontmatterLineOrDefault(t, "status"),
+	}}
+}
+
+// staleInProgressRule flags threads that have sat in an active status
+// without a file change for staleDays or more. File mtime stands in for
+// "git mtime" here, so the check works without shelling out to git.
+type staleInProgressRule struct {
+	staleDays int
+}
+
+func (staleInProgressRule) Name() string { return RuleStaleInProgress }
+
+func (r staleInProgressRule) Check(t *thread.Thread) []Issue {
+	if !isActiveStatus(t.BaseStatus()) {
+		return nil
+	}
+	info, err := os.Stat(t.Path)
+	if err != nil {
+		return nil
+	}
+	if time.Since(info.ModTime()) < time.Duration(r.staleDays)*24*time.Hour {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleStaleInProgress,
+		Message: fmt.Sprintf("in status '%s' with no changes for %d+ days", t.BaseStatus(), r.staleDays),
+		Line:    frontmatterLineOrDefault(t, "status"),
+	}}
+}
+
+func isActiveStatus(status string) bool {
+	for _, s := range thread.ActiveStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateTitleRule flags threads that share another thread's exact
+// name. Built once over the whole corpus at construction time, since
+// Check only ever sees one thread.
+type duplicateTitleRule struct {
+	duplicates map[string]bool
+}
+
+func newDuplicateTitleRule(threads []*thread.Thread) duplicateTitleRule {
+	counts := make(map[string]int)
+	for _, t := range threads {
+		if t.Name() != "" {
+			counts[t.Name()]++
+		}
+	}
+	dup := make(map[string]bool, len(counts))
+	for name, n := range counts {
+		if n > 1 {
+			dup[name] = true
+		}
+	}
+	return duplicateTitleRule{duplicates: dup}
+}
+
+func (duplicateTitleRule) Name() string { return RuleDuplicateTitle }
+
+func (r duplicateTitleRule) Check(t *thread.Thread) []Issue {
+	if !r.duplicates[t.Name()] {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleDuplicateTitle,
+		Message: fmt.Sprintf("name '%s' is shared by another thread", t.Name()),
+		Line:    frontmatterLineOrDefault(t, "name"),
+	}}
+}
+
+// filenameMismatchRule flags threads whose filename slug no longer
+// matches their current name, which happens when a thread is renamed via
+// `threads update` without a corresponding `threads move`.
+type filenameMismatchRule struct{}
+
+func (filenameMismatchRule) Name() string { return RuleFilenameMismatch }
+
+func (filenameMismatchRule) Check(t *thread.Thread) []Issue {
+	wantSlug := workspace.Slugify(t.Name())
+	gotSlug := thread.ExtractNameFromPath(t.Path)
+	if wantSlug == "" || wantSlug == gotSlug {
+		return nil
+	}
+	return []Issue{{
+		RuleID:  RuleFilenameMismatch,
+		Message: fmt.Sprintf("filename slug '%s' doesn't match name '%s' (expected '%s')", gotSlug, t.Name(), wantSlug),
+		Line:    frontmatterLineOrDefault(t, "name"),
+	}}
+}
+
+// crossRefRe matches an inline reference to another thread, e.g.
+// "blocked by #abc123" - a "#" followed by a hex ID, mirroring how
+// workspace.FindByRef resolves abbreviated IDs elsewhere in the tool.
+var crossRefRe = regexp.MustCompile(`#([0-9a-f]{6,40})\b`)
+
+// brokenCrossReferenceRule flags "#<id>" references in a thread's body
+// that don't resolve to any known thread. There's no dedicated
+// parent/related-thread field in Frontmatter, so this is the closest
+// approximation of "orphaned parent reference" the data model supports.
+type brokenCrossReferenceRule struct {
+	knownIDs map[string]bool
+}
+
+func newBrokenCrossReferenceRule(threads []*thread.Thread) brokenCrossReferenceRule {
+	known := make(map[string]bool, len(threads))
+	for _, t := range threads {
+		if t.ID() != "" {
+			known[t.ID()] = true
+		}
+	}
+	return brokenCrossReferenceRule{knownIDs: known}
+}
+
+func (brokenCrossReferenceRule) Name() string { return RuleBrokenCrossReference }
+
+func (r brokenCrossReferenceRule) Check(t *thread.Thread) []Issue {
+	var issues []Issue
+	seen := make(map[string]bool)
+	for _, m := range crossRefRe.FindAllStringSubmatch(t.Content, -1) {
+		id := m[1]
+		if len(id)%2 != 0 || r.knownIDs[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		issues = append(issues, Issue{
+			RuleID:  RuleBrokenCrossReference,
+			Message: fmt.Sprintf("reference to unknown thread '#%s'", id),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,126 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.zib.de/cspiegel/threads/internal/thread"
+)
+
+func writeTestThread(t *testing.T, dir, filename, content string) *thread.Thread {
+	t.Helper()
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := thread.Parse(path)
+	if err != nil {
+		t.Fatalf("thread.Parse(%q): %v", path, err)
+	}
+	return th
+}
+
+func ruleIDs(issues []Issue) []string {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.RuleID
+	}
+	return ids
+}
+
+func TestMissingNameAndStatusRules(t *testing.T) {
+	dir := t.TempDir()
+	th := writeTestThread(t, dir, "abc123-blank.md", "---\nid: abc123\n---\n\nbody\n")
+
+	var issues []Issue
+	issues = append(issues, missingNameRule{}.Check(th)...)
+	issues = append(issues, missingStatusRule{}.Check(th)...)
+
+	got := ruleIDs(issues)
+	want := []string{RuleMissingName, RuleMissingStatus}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+}
+
+func TestInvalidStatusRule(t *testing.T) {
+	dir := t.TempDir()
+	th := writeTestThread(t, dir, "abc123-demo.md", "---\nid: abc123\nname: Demo\nstatus: wip\n---\n\nbody\n")
+
+	issues := invalidStatusRule{}.Check(th)
+	if len(issues) != 1 || issues[0].RuleID != RuleInvalidStatus {
+		t.Fatalf("Check() = %+v, want one invalid-status issue", issues)
+	}
+	if issues[0].Line != 4 {
+		t.Errorf("Line = %d, want 4 (the status: field)", issues[0].Line)
+	}
+}
+
+func TestStaleInProgressRule(t *testing.T) {
+	dir := t.TempDir()
+	th := writeTestThread(t, dir, "abc123-old.md", "---\nid: abc123\nname: Old\nstatus: active\n---\n\nbody\n")
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(th.Path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := staleInProgressRule{staleDays: 14}
+	issues := rule.Check(th)
+	if len(issues) != 1 || issues[0].RuleID != RuleStaleInProgress {
+		t.Fatalf("Check() = %+v, want one stale-in-progress issue", issues)
+	}
+
+	fresh := writeTestThread(t, dir, "def456-new.md", "---\nid: def456\nname: New\nstatus: active\n---\n\nbody\n")
+	if issues := rule.Check(fresh); len(issues) != 0 {
+		t.Errorf("Check(fresh thread) = %+v, want no issues", issues)
+	}
+}
+
+func TestDuplicateTitleRule(t *testing.T) {
+	dir := t.TempDir()
+	a := writeTestThread(t, dir, "abc123-shared.md", "---\nid: abc123\nname: Shared\nstatus: idea\n---\n")
+	b := writeTestThread(t, dir, "def456-shared.md", "---\nid: def456\nname: Shared\nstatus: idea\n---\n")
+	c := writeTestThread(t, dir, "111111-unique.md", "---\nid: 111111\nname: Unique\nstatus: idea\n---\n")
+
+	rule := newDuplicateTitleRule([]*thread.Thread{a, b, c})
+
+	if issues := rule.Check(a); len(issues) != 1 || issues[0].RuleID != RuleDuplicateTitle {
+		t.Errorf("Check(a) = %+v, want one duplicate-title issue", issues)
+	}
+	if issues := rule.Check(c); len(issues) != 0 {
+		t.Errorf("Check(c) = %+v, want no issues", issues)
+	}
+}
+
+func TestFilenameMismatchRule(t *testing.T) {
+	dir := t.TempDir()
+	mismatched := writeTestThread(t, dir, "abc123-old-name.md", "---\nid: abc123\nname: New Name\nstatus: idea\n---\n")
+	matched := writeTestThread(t, dir, "def456-new-name.md", "---\nid: def456\nname: New Name\nstatus: idea\n---\n")
+
+	rule := filenameMismatchRule{}
+	if issues := rule.Check(mismatched); len(issues) != 1 || issues[0].RuleID != RuleFilenameMismatch {
+		t.Errorf("Check(mismatched) = %+v, want one filename-mismatch issue", issues)
+	}
+	if issues := rule.Check(matched); len(issues) != 0 {
+		t.Errorf("Check(matched) = %+v, want no issues", issues)
+	}
+}
+
+func TestBrokenCrossReferenceRule(t *testing.T) {
+	dir := t.TempDir()
+	known := writeTestThread(t, dir, "abc123-known.md", "---\nid: abc123\nname: Known\nstatus: idea\n---\n")
+	referrer := writeTestThread(t, dir, "def456-referrer.md", "---\nid: def456\nname: Referrer\nstatus: idea\n---\n\nBlocked by #abc123 and #999999.\n")
+
+	rule := newBrokenCrossReferenceRule([]*thread.Thread{known, referrer})
+
+	issues := rule.Check(referrer)
+	if len(issues) != 1 {
+		t.Fatalf("Check(referrer) = %+v, want one broken-cross-reference issue", issues)
+	}
+	if issues[0].RuleID != RuleBrokenCrossReference {
+		t.Errorf("RuleID = %q, want %q", issues[0].RuleID, RuleBrokenCrossReference)
+	}
+}
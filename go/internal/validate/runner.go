@@ -0,0 +1,80 @@
+package validate
+
+import "git.zib.de/cspiegel/threads/internal/thread"
+
+// Runner holds the ruleset a workspace's .threads.yaml resolves to -
+// which rules are enabled and at what severity - ready to check threads
+// against it.
+type Runner struct {
+	rules    []Rule
+	severity map[string]Severity
+}
+
+// NewRunner builds a Runner for gitRoot's .threads.yaml (or the full
+// built-in ruleset at default severities, if the file is absent). threads
+// should be every thread in the workspace, not just the ones about to be
+// checked, so corpus-aware rules (duplicate-title, broken-cross-reference)
+// see the full picture.
+func NewRunner(gitRoot string, threads []*thread.Thread) (*Runner, error) {
+	cfg, err := LoadConfig(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := selectRules(newBuiltinRules(cfg.Rules, threads), cfg.Rules)
+
+	severity := make(map[string]Severity, len(defaultSeverity))
+	for id, sev := range defaultSeverity {
+		severity[id] = sev
+	}
+	for id, sev := range cfg.Rules.Severity {
+		severity[id] = Severity(sev)
+	}
+
+	return &Runner{rules: rules, severity: severity}, nil
+}
+
+// selectRules filters all down to cfg's enabled/disabled lists: a
+// non-empty Enabled restricts the set to exactly those rule IDs; Disabled
+// then removes any of those IDs from whatever's left.
+func selectRules(all []Rule, cfg RulesConfig) []Rule {
+	enabled := toSet(cfg.Enabled)
+	disabled := toSet(cfg.Disabled)
+
+	var out []Rule
+	for _, r := range all {
+		if len(enabled) > 0 && !enabled[r.Name()] {
+			continue
+		}
+		if disabled[r.Name()] {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// Check runs every enabled rule over t, stamping each Issue with its
+// rule's configured severity.
+func (r *Runner) Check(t *thread.Thread) []Issue {
+	var issues []Issue
+	for _, rule := range r.rules {
+		for _, issue := range rule.Check(t) {
+			if sev, ok := r.severity[issue.RuleID]; ok {
+				issue.Severity = sev
+			} else if issue.Severity == "" {
+				issue.Severity = SeverityError
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,104 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.zib.de/cspiegel/threads/internal/thread"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := LoadConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Rules.StaleDays != defaultStaleDays {
+		t.Errorf("StaleDays = %d, want %d", cfg.Rules.StaleDays, defaultStaleDays)
+	}
+	if len(cfg.Rules.Enabled) != 0 || len(cfg.Rules.Disabled) != 0 {
+		t.Errorf("Enabled/Disabled should be empty by default, got %+v", cfg.Rules)
+	}
+}
+
+func TestLoadConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+	content := "rules:\n  disabled: [stale-in-progress]\n  severity:\n    duplicate-title: error\n  stale_days: 7\n"
+	if err := os.WriteFile(filepath.Join(dir, ".threads.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Rules.StaleDays != 7 {
+		t.Errorf("StaleDays = %d, want 7", cfg.Rules.StaleDays)
+	}
+	if len(cfg.Rules.Disabled) != 1 || cfg.Rules.Disabled[0] != RuleStaleInProgress {
+		t.Errorf("Disabled = %v, want [%s]", cfg.Rules.Disabled, RuleStaleInProgress)
+	}
+	if cfg.Rules.Severity[RuleDuplicateTitle] != "error" {
+		t.Errorf("Severity[%s] = %q, want error", RuleDuplicateTitle, cfg.Rules.Severity[RuleDuplicateTitle])
+	}
+}
+
+func TestRunnerRespectsDisabledAndSeverity(t *testing.T) {
+	dir := t.TempDir()
+	content := "rules:\n  disabled: [missing-status]\n  severity:\n    missing-name: warning\n"
+	if err := os.WriteFile(filepath.Join(dir, ".threads.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "abc123-blank.md")
+	if err := os.WriteFile(path, []byte("---\nid: abc123\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := thread.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := NewRunner(dir, []*thread.Thread{th})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	issues := runner.Check(th)
+	if len(issues) != 1 {
+		t.Fatalf("Check() = %+v, want exactly one issue (missing-status disabled)", issues)
+	}
+	if issues[0].RuleID != RuleMissingName {
+		t.Errorf("RuleID = %q, want %q", issues[0].RuleID, RuleMissingName)
+	}
+	if issues[0].Severity != SeverityWarning {
+		t.Errorf("Severity = %q, want %q (overridden)", issues[0].Severity, SeverityWarning)
+	}
+}
+
+func TestRunnerEnabledRestrictsRuleset(t *testing.T) {
+	dir := t.TempDir()
+	content := "rules:\n  enabled: [missing-name]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".threads.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "abc123-blank.md")
+	if err := os.WriteFile(path, []byte("---\nid: abc123\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := thread.Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := NewRunner(dir, []*thread.Thread{th})
+	if err != nil {
+		t.Fatalf("NewRunner: %v", err)
+	}
+
+	issues := runner.Check(th)
+	if len(issues) != 1 || issues[0].RuleID != RuleMissingName {
+		t.Fatalf("Check() = %+v, want only missing-name (enabled list restricts ruleset)", issues)
+	}
+}
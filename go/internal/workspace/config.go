@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultIDLength = 6
+	minIDLength     = 6
+	maxIDLength     = 40
+)
+
+// Config is threads' repo-level configuration, read from
+// gitRoot/.threads/config.yaml. Every field defaults to the pre-config
+// behavior, so a repo without this file is unaffected.
+type Config struct {
+	// IDLength is the hex length of generated thread IDs: even, 6-40.
+	IDLength int `yaml:"id_length"`
+	// IDSource selects the IDGenerator: "random" (default) or "content".
+	IDSource string `yaml:"id_source"`
+}
+
+func configPath(gitRoot string) string {
+	return filepath.Join(gitRoot, ".threads", "config.yaml")
+}
+
+// LoadConfig reads gitRoot's repo-level config, defaulting IDLength to 6
+// and IDSource to "random" when the file is missing or a field is unset.
+func LoadConfig(gitRoot string) (Config, error) {
+	cfg := Config{IDLength: defaultIDLength, IDSource: "random"}
+
+	data, err := os.ReadFile(configPath(gitRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("reading %s: %w", configPath(gitRoot), err)
+	}
+
+	var onDisk Config
+	if err := yaml.Unmarshal(data, &onDisk); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", configPath(gitRoot), err)
+	}
+	if onDisk.IDLength > 0 {
+		cfg.IDLength = onDisk.IDLength
+	}
+	if onDisk.IDSource != "" {
+		cfg.IDSource = onDisk.IDSource
+	}
+
+	if cfg.IDLength%2 != 0 || cfg.IDLength < minIDLength || cfg.IDLength > maxIDLength {
+		return cfg, fmt.Errorf("invalid id_length %d in %s: must be an even number between %d and %d",
+			cfg.IDLength, configPath(gitRoot), minIDLength, maxIDLength)
+	}
+
+	return cfg, nil
+}
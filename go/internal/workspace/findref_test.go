@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupThreadsForRef(t *testing.T) string {
+	t.Helper()
+	gitRoot := t.TempDir()
+	threadsDir := filepath.Join(gitRoot, ".threads")
+	if err := os.MkdirAll(threadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"abc123-alpha.md", "abcdef-beta.md", "dead00-gamma.md"} {
+		if err := os.WriteFile(filepath.Join(threadsDir, name), []byte("# thread\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return gitRoot
+}
+
+func TestFindByRefAbbreviatedID(t *testing.T) {
+	gitRoot := setupThreadsForRef(t)
+
+	tests := []struct {
+		name     string
+		ref      string
+		wantFile string
+		wantErr  string
+	}{
+		{name: "unique 2-char prefix", ref: "de", wantFile: "dead00-gamma.md"},
+		{name: "unique 4-char prefix", ref: "dead", wantFile: "dead00-gamma.md"},
+		{name: "full 6-char ID", ref: "abc123", wantFile: "abc123-alpha.md"},
+		{name: "ambiguous prefix", ref: "abc", wantErr: "ambiguous ID"},
+		{name: "no match", ref: "ffffff", wantErr: "thread not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindByRef(gitRoot, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("FindByRef(%q) = %q, want error containing %q", tt.ref, got, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FindByRef(%q) error = %q, want it to contain %q", tt.ref, err.Error(), tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindByRef(%q): %v", tt.ref, err)
+			}
+			if filepath.Base(got) != tt.wantFile {
+				t.Errorf("FindByRef(%q) = %q, want %q", tt.ref, filepath.Base(got), tt.wantFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package workspace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IDGenerator produces one candidate thread ID per call. GenerateID (or
+// GenerateIDForSeed) calls it repeatedly, retrying on collision with an
+// existing thread, until it finds one that's free or gives up.
+type IDGenerator interface {
+	Generate() (string, error)
+}
+
+// RandomIDGenerator produces IDs from cryptographically random bytes,
+// hex-encoded. Bytes sets the ID length (2*Bytes hex chars); the repo
+// default is 3, i.e. the original 6-character ID.
+type RandomIDGenerator struct {
+	Bytes int
+}
+
+// Generate implements IDGenerator.
+func (g RandomIDGenerator) Generate() (string, error) {
+	n := g.Bytes
+	if n <= 0 {
+		n = defaultIDLength / 2
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ContentIDGenerator derives a reproducible ID from Seed instead of
+// randomness: the first Length hex chars of SHA-256(Seed), the same
+// content-addressing idea thread.ItemID uses for notes and todos. Two
+// threads given the same seed get the same ID, so callers should seed
+// with something that's actually unique per thread (e.g. title and
+// creation timestamp).
+type ContentIDGenerator struct {
+	Seed   string
+	Length int
+}
+
+// Generate implements IDGenerator.
+func (g ContentIDGenerator) Generate() (string, error) {
+	length := g.Length
+	if length <= 0 {
+		length = defaultIDLength
+	}
+	sum := sha256.Sum256([]byte(g.Seed))
+	full := hex.EncodeToString(sum[:])
+	if length > len(full) {
+		length = len(full)
+	}
+	return full[:length], nil
+}
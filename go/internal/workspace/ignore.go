@@ -0,0 +1,209 @@
+package workspace
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignorePattern is a single compiled line from a .gitignore/.threadsignore
+// file, scoped to the directory it was read from.
+type ignorePattern struct {
+	negate   bool     // "!" prefix: re-include a previously ignored path
+	dirOnly  bool     // trailing "/": only matches directories
+	anchored bool     // pattern contained a "/" before its end: match from baseDir only
+	segments []string // pattern split on "/", used for anchored matching
+	basename string   // pattern, used for unanchored (basename) matching
+}
+
+// ignoreScope holds the patterns contributed by one directory's ignore file,
+// which only apply to that directory and its descendants.
+type ignoreScope struct {
+	baseDir string // absolute directory the patterns were read from
+	rules   []ignorePattern
+}
+
+// ignoreSet aggregates ignoreScopes from the git root down to each directory
+// visited during a walk. Patterns from shallower directories are stored
+// first; on a match, deeper (more specific) patterns take precedence, same
+// as git itself.
+type ignoreSet struct {
+	scopes []ignoreScope
+}
+
+// loadIgnoreFile parses filename (".gitignore" or ".threadsignore") in dir,
+// if present. A missing file yields a nil, non-error result.
+func loadIgnoreFile(dir, filename string) (*ignoreScope, error) {
+	f, err := os.Open(filepath.Join(dir, filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []ignorePattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if p, ok := compileIgnoreLine(line); ok {
+			rules = append(rules, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return &ignoreScope{baseDir: dir, rules: rules}, nil
+}
+
+// compileIgnoreLine compiles a single gitignore-syntax line. Blank lines and
+// comments ("#") are skipped (ok == false).
+func compileIgnoreLine(line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	var p ignorePattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	// A leading backslash escapes a literal "!" or "#".
+	line = strings.TrimPrefix(line, "\\")
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+
+	trimmed := strings.TrimPrefix(line, "/")
+	if strings.Contains(trimmed, "/") || strings.HasPrefix(line, "/") {
+		p.anchored = true
+		p.segments = strings.Split(trimmed, "/")
+	} else {
+		p.basename = line
+	}
+	return p, true
+}
+
+// newIgnoreSet builds an empty set anchored nowhere; scopes are added as a
+// walk descends via extend.
+func newIgnoreSet() *ignoreSet {
+	return &ignoreSet{}
+}
+
+// buildIgnoreSetForDir loads the ignore files for every directory between
+// gitRoot and dir (inclusive), so a walk rooted at dir starts with the full
+// chain of applicable rules rather than just dir's own ignore file.
+func buildIgnoreSetForDir(gitRoot, dir string) *ignoreSet {
+	absGitRoot, err := filepath.Abs(gitRoot)
+	if err != nil {
+		absGitRoot = gitRoot
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+
+	set := newIgnoreSet().extend(absGitRoot, ".gitignore", ".threadsignore")
+
+	rel, err := filepath.Rel(absGitRoot, absDir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return set
+	}
+
+	cur := absGitRoot
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		cur = filepath.Join(cur, part)
+		set = set.extend(cur, ".gitignore", ".threadsignore")
+	}
+	return set
+}
+
+// extend returns a new ignoreSet with dir's ignore files (any present in
+// filenames) appended, without mutating the receiver, so sibling
+// subdirectories don't see each other's rules.
+func (s *ignoreSet) extend(dir string, filenames ...string) *ignoreSet {
+	next := *s
+	next.scopes = append([]ignoreScope{}, s.scopes...)
+	for _, filename := range filenames {
+		scope, err := loadIgnoreFile(dir, filename)
+		if err != nil || scope == nil {
+			continue
+		}
+		next.scopes = append(next.scopes, *scope)
+	}
+	return &next
+}
+
+// matches reports whether path (absolute) should be ignored, applying
+// scopes from root to leaf and letting the last matching pattern win, so a
+// negation in a more specific scope can re-include a path an earlier scope
+// ignored.
+func (s *ignoreSet) matches(path string, isDir bool) bool {
+	ignored := false
+	for _, scope := range s.scopes {
+		rel, err := filepath.Rel(scope.baseDir, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range scope.rules {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			if p.matches(rel) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// matches reports whether rel (slash-separated, relative to the pattern's
+// base directory) matches this pattern.
+func (p ignorePattern) matches(rel string) bool {
+	if p.anchored {
+		return matchSegments(p.segments, strings.Split(rel, "/"))
+	}
+	// Unanchored: match against any path component's basename.
+	for _, part := range strings.Split(rel, "/") {
+		if ok, _ := filepath.Match(p.basename, part); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches pattern segments (which may contain a "**"
+// wildcard, meaning "zero or more path segments") against the path
+// segments.
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,62 @@
+package workspace
+
+import (
+	"os"
+	"testing"
+)
+
+func TestIgnorePatternMatches(t *testing.T) {
+	tests := []struct {
+		line string
+		rel  string
+		want bool
+	}{
+		{"*.tmp", "foo.tmp", true},
+		{"*.tmp", "sub/foo.tmp", true},
+		{"*.tmp", "foo.md", false},
+		{"/vendor", "vendor", true},
+		{"/vendor", "sub/vendor", false},
+		{"build/", "build", true},
+		{"**/generated", "a/b/generated", true},
+	}
+
+	for _, tt := range tests {
+		p, ok := compileIgnoreLine(tt.line)
+		if !ok {
+			t.Fatalf("compileIgnoreLine(%q) did not compile", tt.line)
+		}
+		if got := p.matches(tt.rel); got != tt.want {
+			t.Errorf("pattern %q matching %q = %v, want %v", tt.line, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreSetNegation(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".gitignore", "*.log\n!keep.log\n")
+
+	set := newIgnoreSet().extend(dir, ".gitignore", ".threadsignore")
+
+	cases := []struct {
+		name string
+		want bool
+	}{
+		{"debug.log", true},
+		{"keep.log", false},
+		{"notes.md", false},
+	}
+
+	for _, c := range cases {
+		path := dir + "/" + c.name
+		if got := set.matches(path, false); got != c.want {
+			t.Errorf("matches(%q) = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(dir+"/"+name, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
@@ -0,0 +1,297 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"git.zib.de/cspiegel/threads/internal/thread"
+)
+
+// indexSchemaVersion guards against an older or newer threads binary
+// reading a format it doesn't understand; a mismatch is treated the same
+// as a missing index.
+const indexSchemaVersion = 1
+
+// Entry is one thread's cached metadata in an Index.
+type Entry struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Path   string `json:"path"`
+	MTime  int64  `json:"mtime"`
+	Size   int64  `json:"size"`
+}
+
+// indexFile is the on-disk representation persisted at
+// <gitRoot>/.git/threads/index: a schema version and a checksum over the
+// entries, conceptually similar to git's own pack index, so a truncated
+// write or a hand-edited file is detected and discarded rather than
+// trusted.
+type indexFile struct {
+	Version  int     `json:"version"`
+	Checksum string  `json:"checksum"`
+	Entries  []Entry `json:"entries"`
+}
+
+// Index is a persistent, on-disk cache of every thread's metadata so that
+// repeated commands against an unchanged tree skip re-parsing every thread
+// file. Refresh still stats every .threads directory (there's no way
+// around discovering new/removed files without looking), but only
+// re-parses a thread whose mtime or size changed since the last refresh.
+type Index struct {
+	gitRoot string
+	path    string
+
+	mu      sync.Mutex
+	entries map[string]Entry // keyed by Path
+	dirty   bool
+}
+
+func indexFilePath(gitRoot string) string {
+	return filepath.Join(gitRoot, ".git", "threads", "index")
+}
+
+// OpenIndex loads gitRoot's on-disk index. A missing, corrupt,
+// version-mismatched, or checksum-mismatched index is not an error: it's
+// treated as an empty index, which Refresh then rebuilds from scratch.
+func OpenIndex(gitRoot string) (*Index, error) {
+	idx := &Index{
+		gitRoot: gitRoot,
+		path:    indexFilePath(gitRoot),
+		entries: make(map[string]Entry),
+	}
+
+	data, err := os.ReadFile(idx.path)
+	if err != nil {
+		return idx, nil
+	}
+
+	var f indexFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return idx, nil
+	}
+	if f.Version != indexSchemaVersion || f.Checksum != checksumEntries(f.Entries) {
+		return idx, nil
+	}
+
+	for _, e := range f.Entries {
+		idx.entries[e.Path] = e
+	}
+	return idx, nil
+}
+
+// checksumEntries hashes entries in Path order, so re-marshaling the same
+// logical set of entries (read from a Go map, in no guaranteed order)
+// always produces the same checksum.
+func checksumEntries(entries []Entry) string {
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	data, _ := json.Marshal(sorted)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// All returns every cached entry, sorted by path.
+func (idx *Index) All() []Entry {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	out := make([]Entry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// Lookup finds the cached entry whose ID or Name matches ref exactly.
+func (idx *Index) Lookup(ref string) (Entry, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, e := range idx.entries {
+		if e.ID == ref || e.Name == ref {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Invalidate drops path's cached entry, so the next Refresh re-parses it
+// rather than trusting a stale mtime/size match.
+func (idx *Index) Invalidate(path string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.entries[path]; ok {
+		delete(idx.entries, path)
+		idx.dirty = true
+	}
+}
+
+// Refresh rescans gitRoot's threads, re-parsing only files whose mtime or
+// size changed since the index was last refreshed, then persists the
+// result to disk.
+func (idx *Index) Refresh() error {
+	paths, err := FindAllThreads(idx.gitRoot)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		seen[p] = true
+
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		mtime := info.ModTime().UnixNano()
+		size := info.Size()
+
+		if existing, ok := idx.entries[p]; ok && existing.MTime == mtime && existing.Size == size {
+			continue
+		}
+
+		t, err := thread.Parse(p)
+		if err != nil {
+			// A thread that fails to parse (bad frontmatter, no
+			// frontmatter at all) still exists on disk and still needs
+			// to be findable by ID - a raw filesystem walk would still
+			// turn it up, so the index shouldn't be the one place that
+			// makes it disappear. Fall back to filename-derived ID/name
+			// and leave Status blank.
+			idx.entries[p] = Entry{
+				ID:    thread.ExtractIDFromPath(p),
+				Name:  thread.ExtractNameFromPath(p),
+				Path:  p,
+				MTime: mtime,
+				Size:  size,
+			}
+			idx.dirty = true
+			continue
+		}
+		idx.entries[p] = Entry{
+			ID:     t.ID(),
+			Name:   thread.ExtractNameFromPath(p),
+			Status: t.Status(),
+			Path:   p,
+			MTime:  mtime,
+			Size:   size,
+		}
+		idx.dirty = true
+	}
+
+	for p := range idx.entries {
+		if !seen[p] {
+			delete(idx.entries, p)
+			idx.dirty = true
+		}
+	}
+	idx.mu.Unlock()
+
+	return idx.save()
+}
+
+// save persists the index if anything changed since it was loaded or last
+// saved.
+func (idx *Index) save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.dirty {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		entries = append(entries, e)
+	}
+
+	f := indexFile{
+		Version:  indexSchemaVersion,
+		Checksum: checksumEntries(entries),
+		Entries:  entries,
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(idx.path, data, 0644); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// InvalidateIndex drops path's cached entry in gitRoot's on-disk index and
+// persists the change immediately, for mutating commands (new, move, log,
+// status changes) that shouldn't wait for the next Refresh to notice their
+// own write. Best-effort: an index that can't be opened or saved is left
+// as-is, since it's a cache, not a source of truth.
+func InvalidateIndex(gitRoot, path string) {
+	idx, err := OpenIndex(gitRoot)
+	if err != nil {
+		return
+	}
+	idx.Invalidate(path)
+	_ = idx.save()
+}
+
+// threadPathsPreferIndex returns gitRoot's thread file paths from its
+// on-disk index when available, refreshing it first so it reflects the
+// current tree, falling back to a full filesystem scan if the index can't
+// be opened or refreshed.
+func threadPathsPreferIndex(gitRoot string) ([]string, error) {
+	if idx, err := OpenIndex(gitRoot); err == nil {
+		if err := idx.Refresh(); err == nil {
+			entries := idx.All()
+			paths := make([]string, len(entries))
+			for i, e := range entries {
+				paths[i] = e.Path
+			}
+			return paths, nil
+		}
+	}
+	return FindAllThreads(gitRoot)
+}
+
+// existingThreadIDs returns every known thread's ID, consulting gitRoot's
+// index (refreshed so it reflects the current tree) and falling back to a
+// full filesystem scan if the index can't be opened or refreshed.
+func existingThreadIDs(gitRoot string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+
+	if idx, err := OpenIndex(gitRoot); err == nil {
+		if err := idx.Refresh(); err == nil {
+			for _, e := range idx.All() {
+				existing[e.ID] = true
+			}
+			return existing, nil
+		}
+	}
+
+	threads, err := FindAllThreads(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range threads {
+		if id := thread.ExtractIDFromPath(t); id != "" {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
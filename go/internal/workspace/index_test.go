@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeIndexTestThread(t *testing.T, gitRoot, filename, status string) string {
+	t.Helper()
+	threadsDir := filepath.Join(gitRoot, ".threads")
+	if err := os.MkdirAll(threadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(threadsDir, filename)
+	content := "---\nid: " + filename[:6] + "\nname: " + filename[7:len(filename)-3] + "\nstatus: " + status + "\n---\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIndexRefreshAndLookup(t *testing.T) {
+	gitRoot := t.TempDir()
+	path := writeIndexTestThread(t, gitRoot, "abc123-demo.md", "idea")
+
+	idx, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	entries := idx.All()
+	if len(entries) != 1 || entries[0].ID != "abc123" {
+		t.Fatalf("All() = %+v, want one entry with ID abc123", entries)
+	}
+
+	e, ok := idx.Lookup("abc123")
+	if !ok || e.Path != path {
+		t.Fatalf("Lookup(abc123) = %+v, ok=%v", e, ok)
+	}
+	if _, ok := idx.Lookup("nonexistent"); ok {
+		t.Error("Lookup(nonexistent) unexpectedly found an entry")
+	}
+}
+
+func TestIndexPersistsAcrossOpen(t *testing.T) {
+	gitRoot := t.TempDir()
+	writeIndexTestThread(t, gitRoot, "abc123-demo.md", "idea")
+
+	idx, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	reopened, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex (reopen): %v", err)
+	}
+	entries := reopened.All()
+	if len(entries) != 1 || entries[0].ID != "abc123" {
+		t.Fatalf("reopened All() = %+v, want one entry with ID abc123", entries)
+	}
+}
+
+func TestIndexRefreshDropsDeletedThreads(t *testing.T) {
+	gitRoot := t.TempDir()
+	path := writeIndexTestThread(t, gitRoot, "abc123-demo.md", "idea")
+
+	idx, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if len(idx.All()) != 1 {
+		t.Fatalf("expected 1 entry before deletion, got %d", len(idx.All()))
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh after delete: %v", err)
+	}
+	if len(idx.All()) != 0 {
+		t.Fatalf("expected 0 entries after deletion, got %d", len(idx.All()))
+	}
+}
+
+func TestInvalidateIndexForcesReparse(t *testing.T) {
+	gitRoot := t.TempDir()
+	path := writeIndexTestThread(t, gitRoot, "abc123-demo.md", "idea")
+
+	idx, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	InvalidateIndex(gitRoot, path)
+
+	reopened, err := OpenIndex(gitRoot)
+	if err != nil {
+		t.Fatalf("OpenIndex (reopen): %v", err)
+	}
+	if len(reopened.All()) != 0 {
+		t.Fatalf("expected invalidated entry to be gone, got %+v", reopened.All())
+	}
+}
+
+func TestGenerateIDUsesIndex(t *testing.T) {
+	gitRoot := t.TempDir()
+	writeIndexTestThread(t, gitRoot, "abc123-demo.md", "idea")
+
+	id, err := GenerateID(gitRoot)
+	if err != nil {
+		t.Fatalf("GenerateID: %v", err)
+	}
+	if id == "abc123" {
+		t.Errorf("GenerateID returned an ID already in use: %s", id)
+	}
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Submodule describes one entry parsed from .gitmodules.
+type Submodule struct {
+	Name string
+	Path string // relative to gitRoot, as recorded in .gitmodules
+}
+
+// LoadSubmodules parses gitRoot's .gitmodules, keyed by submodule path
+// (relative to gitRoot, matching the key findThreadsDown/findThreadsRecursive
+// compare nested git roots against). A missing .gitmodules isn't an error -
+// it just means the repo has no submodules.
+func LoadSubmodules(gitRoot string) (map[string]Submodule, error) {
+	submodules := make(map[string]Submodule)
+
+	f, err := os.Open(filepath.Join(gitRoot, ".gitmodules"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return submodules, nil
+		}
+		return nil, fmt.Errorf("reading .gitmodules: %w", err)
+	}
+	defer f.Close()
+
+	var name string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "[submodule "):
+			name = strings.Trim(strings.TrimPrefix(line, "[submodule "), `"]`)
+		case strings.HasPrefix(line, "path"):
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			path := filepath.Clean(strings.TrimSpace(parts[1]))
+			submodules[path] = Submodule{Name: name, Path: path}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading .gitmodules: %w", err)
+	}
+
+	return submodules, nil
+}
+
+// isSubmodulePath reports whether relPath (relative to gitRoot) names a
+// registered submodule.
+func isSubmodulePath(submodules map[string]Submodule, relPath string) bool {
+	_, ok := submodules[filepath.Clean(relPath)]
+	return ok
+}
@@ -0,0 +1,101 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSubmodules(t *testing.T) {
+	dir := t.TempDir()
+	gitmodules := `[submodule "vendor/lib"]
+	path = vendor/lib
+	url = https://example.com/lib.git
+[submodule "docs"]
+	path = docs-src
+	url = https://example.com/docs.git
+`
+	if err := os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	submodules, err := LoadSubmodules(dir)
+	if err != nil {
+		t.Fatalf("LoadSubmodules: %v", err)
+	}
+	if len(submodules) != 2 {
+		t.Fatalf("len(submodules) = %d, want 2", len(submodules))
+	}
+	if sub, ok := submodules["vendor/lib"]; !ok || sub.Name != "vendor/lib" {
+		t.Errorf("submodules[vendor/lib] = %+v, ok=%v", sub, ok)
+	}
+	if sub, ok := submodules["docs-src"]; !ok || sub.Name != "docs" {
+		t.Errorf("submodules[docs-src] = %+v, ok=%v", sub, ok)
+	}
+}
+
+func TestLoadSubmodulesMissingFile(t *testing.T) {
+	submodules, err := LoadSubmodules(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadSubmodules: %v", err)
+	}
+	if len(submodules) != 0 {
+		t.Errorf("len(submodules) = %d, want 0", len(submodules))
+	}
+}
+
+func TestFindAllThreadsWithOptionsIncludesSubmodules(t *testing.T) {
+	gitRoot := t.TempDir()
+
+	writeThread := func(dir string) {
+		threadsDir := filepath.Join(dir, ".threads")
+		if err := os.MkdirAll(threadsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(threadsDir, "abc123-test.md"), []byte("# test\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeThread(gitRoot)
+
+	// A registered submodule: has its own .git dir, should be descended
+	// into when IncludeSubmodules is set.
+	subPath := filepath.Join(gitRoot, "vendor", "lib")
+	if err := os.MkdirAll(filepath.Join(subPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeThread(subPath)
+
+	// An unrelated nested repo: not in .gitmodules, must stay excluded.
+	otherPath := filepath.Join(gitRoot, "other-repo")
+	if err := os.MkdirAll(filepath.Join(otherPath, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeThread(otherPath)
+
+	gitmodules := "[submodule \"lib\"]\n\tpath = vendor/lib\n"
+	if err := os.WriteFile(filepath.Join(gitRoot, ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := FindAllThreads(gitRoot)
+	if err != nil {
+		t.Fatalf("FindAllThreads: %v", err)
+	}
+	if len(plain) != 1 {
+		t.Fatalf("FindAllThreads (no submodule support) = %v, want 1 entry", plain)
+	}
+
+	withSubs, err := FindAllThreadsWithOptions(gitRoot, NewFindOptions().WithIncludeSubmodules(true))
+	if err != nil {
+		t.Fatalf("FindAllThreadsWithOptions: %v", err)
+	}
+	if len(withSubs) != 2 {
+		t.Fatalf("FindAllThreadsWithOptions = %v, want 2 entries (root + submodule, not other-repo)", withSubs)
+	}
+	for _, p := range withSubs {
+		if filepath.Dir(filepath.Dir(p)) == otherPath {
+			t.Errorf("FindAllThreadsWithOptions included unrelated nested repo: %s", p)
+		}
+	}
+}
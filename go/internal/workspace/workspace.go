@@ -1,16 +1,14 @@
 package workspace
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
+	"git.zib.de/cspiegel/threads/internal/git"
 	"git.zib.de/cspiegel/threads/internal/thread"
 )
 
@@ -18,7 +16,9 @@ import (
 var (
 	nonAlphanumRe = regexp.MustCompile(`[^a-z0-9]+`)
 	multiHyphenRe = regexp.MustCompile(`-+`)
-	hexIDRe       = regexp.MustCompile(`^[0-9a-f]{6}$`)
+	// hexPrefixRe matches a git-style abbreviated thread ID: 2 chars is the
+	// shortest usable prefix, 40 the longest ID length IDLength allows.
+	hexPrefixRe = regexp.MustCompile(`^[0-9a-f]{2,40}$`)
 )
 
 // FindOptions contains options for finding threads with direction and boundary controls.
@@ -31,6 +31,16 @@ type FindOptions struct {
 	NoGitBoundDown bool
 	// NoGitBoundUp allows crossing git boundaries when searching up
 	NoGitBoundUp bool
+	// NoIgnore disables .gitignore/.threadsignore filtering entirely
+	NoIgnore bool
+	// WorktreeShared also walks the main worktree's tree when gitRoot is a
+	// linked worktree, so threads filed from either checkout stay visible
+	// from both.
+	WorktreeShared bool
+	// IncludeSubmodules recurses into directories registered as submodules
+	// in .gitmodules instead of stopping at their nested git boundary,
+	// while still skipping unrelated nested repos.
+	IncludeSubmodules bool
 }
 
 // NewFindOptions creates FindOptions with default values.
@@ -64,6 +74,26 @@ func (o *FindOptions) WithNoGitBoundUp(value bool) *FindOptions {
 	return o
 }
 
+// WithNoIgnore disables .gitignore/.threadsignore filtering during the walk.
+func (o *FindOptions) WithNoIgnore(value bool) *FindOptions {
+	o.NoIgnore = value
+	return o
+}
+
+// WithWorktreeShared enables also searching the main worktree's tree when
+// gitRoot turns out to be a linked worktree.
+func (o *FindOptions) WithWorktreeShared(value bool) *FindOptions {
+	o.WorktreeShared = value
+	return o
+}
+
+// WithIncludeSubmodules enables recursing into directories registered as
+// submodules in .gitmodules.
+func (o *FindOptions) WithIncludeSubmodules(value bool) *FindOptions {
+	o.IncludeSubmodules = value
+	return o
+}
+
 // HasDown returns true if down searching is enabled.
 func (o *FindOptions) HasDown() bool {
 	return o.Down != nil
@@ -102,30 +132,23 @@ func Find() (string, error) {
 	return FindGitRoot()
 }
 
-// FindGitRoot uses git rev-parse --show-toplevel to find the repository root.
+// FindGitRoot discovers the repository root from the current directory,
+// via the configured git.Backend (see git.DiscoverRoot).
 func FindGitRoot() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	root, err := git.DiscoverRoot(".")
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository. threads requires a git repo to define scope")
 	}
-
-	root := strings.TrimSpace(string(output))
-	if root == "" {
-		return "", fmt.Errorf("git root is empty")
-	}
-
 	return root, nil
 }
 
-// FindGitRootForPath finds the git root for a specific path.
+// FindGitRootForPath discovers the git root for a specific path.
 func FindGitRootForPath(path string) (string, error) {
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	root, err := git.DiscoverRoot(path)
 	if err != nil {
 		return "", fmt.Errorf("not in a git repository at: %s", path)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return root, nil
 }
 
 // IsGitRoot checks if a directory contains a .git folder.
@@ -135,19 +158,41 @@ func IsGitRoot(path string) bool {
 }
 
 // FindAllThreads returns all thread file paths within the git root.
-// Scans recursively, respecting git boundaries (stops at nested git repos).
+// Scans recursively, respecting git boundaries (stops at nested git repos)
+// and .gitignore/.threadsignore rules.
 func FindAllThreads(gitRoot string) ([]string, error) {
+	return FindAllThreadsWithOptions(gitRoot, NewFindOptions())
+}
+
+// FindAllThreadsWithOptions is FindAllThreads with IncludeSubmodules
+// support: when set, directories registered as submodules in .gitmodules
+// are descended into instead of treated as a nested-repo boundary.
+func FindAllThreadsWithOptions(gitRoot string, options *FindOptions) ([]string, error) {
+	var submodules map[string]Submodule
+	if options.IncludeSubmodules {
+		loaded, err := LoadSubmodules(gitRoot)
+		if err != nil {
+			return nil, err
+		}
+		submodules = loaded
+	}
+
 	var threads []string
-	if err := findThreadsRecursive(gitRoot, gitRoot, &threads); err != nil {
+	ignores := newIgnoreSet().extend(gitRoot, ".gitignore", ".threadsignore")
+	if err := findThreadsRecursive(gitRoot, gitRoot, &threads, ignores, submodules); err != nil {
 		return nil, err
 	}
 	sort.Strings(threads)
 	return threads, nil
 }
 
-// findThreadsRecursive recursively finds .threads directories and collects thread files.
-// Stops at nested git repositories (directories containing .git).
-func findThreadsRecursive(dir, gitRoot string, threads *[]string) error {
+// findThreadsRecursive recursively finds .threads directories and collects
+// thread files. Stops at nested git repositories (directories containing
+// .git) and at paths excluded by ignores, except a nested repo whose path
+// (relative to gitRoot) matches an entry in submodules, which is descended
+// into like any other directory. submodules is nil when submodule
+// traversal isn't enabled.
+func findThreadsRecursive(dir, gitRoot string, threads *[]string, ignores *ignoreSet, submodules map[string]Submodule) error {
 	// Check for .threads directory here
 	threadsDir := filepath.Join(dir, ".threads")
 	if info, err := os.Stat(threadsDir); err == nil && info.IsDir() {
@@ -160,7 +205,7 @@ func findThreadsRecursive(dir, gitRoot string, threads *[]string) error {
 				if strings.HasSuffix(entry.Name(), ".md") {
 					path := filepath.Join(threadsDir, entry.Name())
 					// Skip archive subdirectory
-					if !strings.Contains(path, "/archive/") {
+					if !strings.Contains(path, "/archive/") && !ignores.matches(path, false) {
 						*threads = append(*threads, path)
 					}
 				}
@@ -188,12 +233,20 @@ func findThreadsRecursive(dir, gitRoot string, threads *[]string) error {
 
 		subdir := filepath.Join(dir, name)
 
-		// Stop at nested git repos (unless it's the root itself)
+		// Stop at nested git repos (unless it's the root itself, or it's a
+		// registered submodule and we're crossing into those on purpose).
 		if subdir != gitRoot && IsGitRoot(subdir) {
+			relPath, err := filepath.Rel(gitRoot, subdir)
+			if err != nil || !isSubmodulePath(submodules, relPath) {
+				continue
+			}
+		}
+
+		if ignores.matches(subdir, true) {
 			continue
 		}
 
-		findThreadsRecursive(subdir, gitRoot, threads)
+		findThreadsRecursive(subdir, gitRoot, threads, ignores.extend(subdir, ".gitignore", ".threadsignore"), submodules)
 	}
 
 	return nil
@@ -209,19 +262,43 @@ func FindThreadsWithOptions(startPath, gitRoot string, options *FindOptions) ([]
 		absStart = startPath
 	}
 
+	ignores := newIgnoreSet()
+	if !options.NoIgnore {
+		ignores = buildIgnoreSetForDir(gitRoot, absStart)
+	}
+
+	var submodules map[string]Submodule
+	if options.IncludeSubmodules {
+		loaded, err := LoadSubmodules(gitRoot)
+		if err != nil {
+			return nil, err
+		}
+		submodules = loaded
+	}
+
 	// Always collect threads at start_path
-	collectThreadsAtPath(absStart, &threads)
+	collectThreadsAtPath(absStart, &threads, ignores)
 
 	// Search down (subdirectories)
 	if options.HasDown() {
 		maxDepth := options.DownDepth()
-		findThreadsDown(absStart, gitRoot, &threads, 0, maxDepth, options.NoGitBoundDown)
+		findThreadsDown(absStart, gitRoot, &threads, 0, maxDepth, options.NoGitBoundDown, ignores, submodules)
 	}
 
 	// Search up (parent directories)
 	if options.HasUp() {
 		maxDepth := options.UpDepth()
-		findThreadsUp(absStart, gitRoot, &threads, 0, maxDepth, options.NoGitBoundUp)
+		findThreadsUp(absStart, gitRoot, &threads, 0, maxDepth, options.NoGitBoundUp, ignores)
+	}
+
+	// Also pull in the main worktree's threads: a linked worktree otherwise
+	// only ever sees its own disjoint .threads tree.
+	if options.WorktreeShared {
+		if mainPath, err := MainWorktreePath(gitRoot); err == nil && mainPath != gitRoot {
+			if mainThreads, err := FindAllThreads(mainPath); err == nil {
+				threads = append(threads, mainThreads...)
+			}
+		}
 	}
 
 	// Sort and deduplicate
@@ -232,7 +309,7 @@ func FindThreadsWithOptions(startPath, gitRoot string, options *FindOptions) ([]
 }
 
 // collectThreadsAtPath collects threads from .threads directory at the given path.
-func collectThreadsAtPath(dir string, threads *[]string) {
+func collectThreadsAtPath(dir string, threads *[]string, ignores *ignoreSet) {
 	threadsDir := filepath.Join(dir, ".threads")
 	if info, err := os.Stat(threadsDir); err == nil && info.IsDir() {
 		entries, err := os.ReadDir(threadsDir)
@@ -244,7 +321,7 @@ func collectThreadsAtPath(dir string, threads *[]string) {
 				if strings.HasSuffix(entry.Name(), ".md") {
 					path := filepath.Join(threadsDir, entry.Name())
 					// Skip archive subdirectory
-					if !strings.Contains(path, "/archive/") {
+					if !strings.Contains(path, "/archive/") && !ignores.matches(path, false) {
 						*threads = append(*threads, path)
 					}
 				}
@@ -254,7 +331,10 @@ func collectThreadsAtPath(dir string, threads *[]string) {
 }
 
 // findThreadsDown recursively finds threads going down into subdirectories.
-func findThreadsDown(dir, gitRoot string, threads *[]string, currentDepth, maxDepth int, crossGitBoundaries bool) {
+// A nested git repo matching an entry in submodules (keyed by path relative
+// to gitRoot) is descended into even when crossGitBoundaries is false;
+// submodules is nil when submodule traversal isn't enabled.
+func findThreadsDown(dir, gitRoot string, threads *[]string, currentDepth, maxDepth int, crossGitBoundaries bool, ignores *ignoreSet, submodules map[string]Submodule) {
 	// Check depth limit (-1 means unlimited)
 	if maxDepth >= 0 && currentDepth >= maxDepth {
 		return
@@ -279,21 +359,31 @@ func findThreadsDown(dir, gitRoot string, threads *[]string, currentDepth, maxDe
 
 		subdir := filepath.Join(dir, name)
 
-		// Check git boundary
+		// Check git boundary, unless subdir is a registered submodule.
 		if !crossGitBoundaries && subdir != gitRoot && IsGitRoot(subdir) {
+			relPath, err := filepath.Rel(gitRoot, subdir)
+			if err != nil || !isSubmodulePath(submodules, relPath) {
+				continue
+			}
+		}
+
+		if ignores.matches(subdir, true) {
 			continue
 		}
+		subIgnores := ignores.extend(subdir, ".gitignore", ".threadsignore")
 
 		// Collect threads at this level
-		collectThreadsAtPath(subdir, threads)
+		collectThreadsAtPath(subdir, threads, subIgnores)
 
 		// Continue recursing
-		findThreadsDown(subdir, gitRoot, threads, currentDepth+1, maxDepth, crossGitBoundaries)
+		findThreadsDown(subdir, gitRoot, threads, currentDepth+1, maxDepth, crossGitBoundaries, subIgnores, submodules)
 	}
 }
 
-// findThreadsUp finds threads going up into parent directories.
-func findThreadsUp(dir, gitRoot string, threads *[]string, currentDepth, maxDepth int, crossGitBoundaries bool) {
+// findThreadsUp finds threads going up into parent directories. ignores is
+// the chain already built from gitRoot down to the search's start path,
+// which covers every ancestor visited here, so it's reused as-is.
+func findThreadsUp(dir, gitRoot string, threads *[]string, currentDepth, maxDepth int, crossGitBoundaries bool, ignores *ignoreSet) {
 	// Check depth limit (-1 means unlimited)
 	if maxDepth >= 0 && currentDepth >= maxDepth {
 		return
@@ -313,10 +403,12 @@ func findThreadsUp(dir, gitRoot string, threads *[]string, currentDepth, maxDept
 	}
 
 	// Collect threads at parent
-	collectThreadsAtPath(absParent, threads)
+	if !ignores.matches(absParent, true) {
+		collectThreadsAtPath(absParent, threads, ignores)
+	}
 
 	// Continue up
-	findThreadsUp(absParent, gitRoot, threads, currentDepth+1, maxDepth, crossGitBoundaries)
+	findThreadsUp(absParent, gitRoot, threads, currentDepth+1, maxDepth, crossGitBoundaries, ignores)
 }
 
 // deduplicate removes duplicate strings from a sorted slice.
@@ -349,7 +441,12 @@ type Scope struct {
 // - "./X/Y": PWD-relative
 // - "/X/Y": Absolute
 // - "X/Y" (no leading ./ or /): Git-root-relative
-func InferScope(gitRoot, pathArg string) (*Scope, error) {
+//
+// If shared is true and gitRoot turns out to be a linked worktree, the
+// returned scope's ThreadsDir is rebased onto the corresponding path in the
+// main worktree instead, so a thread filed there is visible from every
+// worktree sharing the same git common directory.
+func InferScope(gitRoot, pathArg string, shared bool) (*Scope, error) {
 	pwd, err := os.Getwd()
 	if err != nil {
 		return nil, fmt.Errorf("cannot get current directory: %w", err)
@@ -427,8 +524,15 @@ func InferScope(gitRoot, pathArg string) (*Scope, error) {
 		levelDesc = "repo root"
 	}
 
-	// Build threads directory path
-	threadsDir := filepath.Join(absTarget, ".threads")
+	// Build threads directory path, rebased onto the main worktree if shared
+	// placement was requested and we're actually in a linked worktree.
+	target := absTarget
+	if shared {
+		if mainPath, err := MainWorktreePath(absGitRoot); err == nil && mainPath != absGitRoot {
+			target = filepath.Join(mainPath, relPath)
+		}
+	}
+	threadsDir := filepath.Join(target, ".threads")
 
 	return &Scope{
 		ThreadsDir: threadsDir,
@@ -487,28 +591,43 @@ func PWDRelativeToGitRoot(gitRoot string) (string, error) {
 	return PathRelativeToGitRoot(gitRoot, pwd), nil
 }
 
-// GenerateID creates a unique 6-character hex ID.
+// GenerateID creates a unique thread ID per gitRoot's configured IDSource
+// and IDLength (.threads/config.yaml), defaulting to a random 6-character
+// hex ID when unconfigured.
 func GenerateID(gitRoot string) (string, error) {
-	existing := make(map[string]bool)
+	return GenerateIDForSeed(gitRoot, "")
+}
 
-	threads, err := FindAllThreads(gitRoot)
+// GenerateIDForSeed is GenerateID, but passes seed through to a
+// ContentIDGenerator when the repo's config selects id_source: content.
+// seed is ignored under the default random source; callers with natural
+// seed material (e.g. a thread's title and creation time) should prefer
+// this over GenerateID.
+func GenerateIDForSeed(gitRoot, seed string) (string, error) {
+	cfg, err := LoadConfig(gitRoot)
 	if err != nil {
 		return "", err
 	}
 
-	for _, t := range threads {
-		if id := thread.ExtractIDFromPath(t); id != "" {
-			existing[id] = true
-		}
+	var gen IDGenerator
+	switch cfg.IDSource {
+	case "content":
+		gen = ContentIDGenerator{Seed: seed, Length: cfg.IDLength}
+	default:
+		gen = RandomIDGenerator{Bytes: cfg.IDLength / 2}
+	}
+
+	existing, err := existingThreadIDs(gitRoot)
+	if err != nil {
+		return "", err
 	}
 
 	// Try to generate unique ID
 	for i := 0; i < 10; i++ {
-		bytes := make([]byte, 3)
-		if _, err := rand.Read(bytes); err != nil {
+		id, err := gen.Generate()
+		if err != nil {
 			return "", err
 		}
-		id := hex.EncodeToString(bytes)
 		if !existing[id] {
 			return id, nil
 		}
@@ -536,17 +655,33 @@ func Slugify(title string) string {
 
 // FindByRef locates a thread by ID or name (with fuzzy matching).
 func FindByRef(gitRoot, ref string) (string, error) {
-	threads, err := FindAllThreads(gitRoot)
+	threads, err := threadPathsPreferIndex(gitRoot)
 	if err != nil {
 		return "", err
 	}
 
-	// Fast path: exact ID match
-	if hexIDRe.MatchString(ref) {
+	// Fast path: git-style abbreviated ID. Any hex prefix from 2 chars up to
+	// the full ID length (at most 40) resolves if it uniquely identifies one
+	// thread's ID, mirroring short-SHA lookup.
+	if hexPrefixRe.MatchString(ref) {
+		var idMatches []string
 		for _, t := range threads {
-			if thread.ExtractIDFromPath(t) == ref {
-				return t, nil
+			if strings.HasPrefix(thread.ExtractIDFromPath(t), ref) {
+				idMatches = append(idMatches, t)
+			}
+		}
+		if len(idMatches) == 1 {
+			return idMatches[0], nil
+		}
+		if len(idMatches) > 1 {
+			var ids []string
+			for _, m := range idMatches {
+				id := thread.ExtractIDFromPath(m)
+				name := thread.ExtractNameFromPath(m)
+				ids = append(ids, fmt.Sprintf("%s (%s)", id, name))
 			}
+			return "", fmt.Errorf("ambiguous ID '%s' matches %d threads: %s",
+				ref, len(idMatches), strings.Join(ids, ", "))
 		}
 	}
 
@@ -0,0 +1,105 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Worktree describes a linked git worktree discovered alongside the current
+// repository.
+type Worktree struct {
+	Name string // worktree name, as used under <gitdir>/worktrees/<name>
+	Path string // absolute path to the worktree's working directory
+}
+
+// ListWorktrees returns the linked worktrees (excluding gitRoot itself)
+// registered against gitRoot's repository, sorted by name.
+func ListWorktrees(gitRoot string) ([]Worktree, error) {
+	commonDir, err := resolveGitDir(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading worktrees: %w", err)
+	}
+
+	var worktrees []Worktree
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		gitdirFile := filepath.Join(commonDir, "worktrees", entry.Name(), "gitdir")
+		data, err := os.ReadFile(gitdirFile)
+		if err != nil {
+			continue
+		}
+		// gitdir points at <worktree-path>/.git
+		dotGit := strings.TrimSpace(string(data))
+		path := filepath.Dir(dotGit)
+		if path == "" || path == gitRoot {
+			continue
+		}
+		worktrees = append(worktrees, Worktree{Name: entry.Name(), Path: path})
+	}
+
+	sort.Slice(worktrees, func(i, j int) bool { return worktrees[i].Name < worktrees[j].Name })
+	return worktrees, nil
+}
+
+// FindGitCommonDir returns the repository's shared git directory: gitRoot's
+// own .git directory, or, if gitRoot is a linked worktree, the main
+// worktree's .git directory that it's registered against - the pure-Go
+// equivalent of `git rev-parse --git-common-dir`.
+func FindGitCommonDir(gitRoot string) (string, error) {
+	return resolveGitDir(gitRoot)
+}
+
+// MainWorktreePath returns the absolute path of the main worktree that
+// gitRoot's repository is checked out at. If gitRoot is itself the main
+// worktree, it returns gitRoot.
+func MainWorktreePath(gitRoot string) (string, error) {
+	commonDir, err := resolveGitDir(gitRoot)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(commonDir), nil
+}
+
+// resolveGitDir returns the common git directory for gitRoot: the .git
+// directory itself, or, if gitRoot is a linked worktree, the main repository's
+// git directory referenced by its .git file.
+func resolveGitDir(gitRoot string) (string, error) {
+	dotGit := filepath.Join(gitRoot, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("no .git entry at %s: %w", gitRoot, err)
+	}
+
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	// Linked worktree: .git is a file containing "gitdir: <path>/.git/worktrees/<name>"
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", dotGit, err)
+	}
+	line := strings.TrimSpace(string(data))
+	path := strings.TrimPrefix(line, "gitdir:")
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("%s did not contain a gitdir reference", dotGit)
+	}
+	if idx := strings.Index(path, string(filepath.Separator)+"worktrees"+string(filepath.Separator)); idx >= 0 {
+		return path[:idx], nil
+	}
+	return path, nil
+}
@@ -0,0 +1,77 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupLinkedWorktree creates a fake main repo at dir/main with a real .git
+// directory plus its worktrees/feature bookkeeping, and a fake linked
+// worktree at dir/feature whose .git file points back at it, without
+// shelling out to git.
+func setupLinkedWorktree(t *testing.T) (mainRoot, linkedRoot string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	mainRoot = filepath.Join(dir, "main")
+	linkedRoot = filepath.Join(dir, "feature")
+
+	commonDir := filepath.Join(mainRoot, ".git")
+	worktreeGitDir := filepath.Join(commonDir, "worktrees", "feature")
+	if err := os.MkdirAll(worktreeGitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(linkedRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(worktreeGitDir, "gitdir"), []byte(linkedRoot+"/.git\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(linkedRoot, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return mainRoot, linkedRoot
+}
+
+func TestFindGitCommonDir(t *testing.T) {
+	mainRoot, linkedRoot := setupLinkedWorktree(t)
+
+	got, err := FindGitCommonDir(mainRoot)
+	if err != nil {
+		t.Fatalf("FindGitCommonDir(main): %v", err)
+	}
+	if want := filepath.Join(mainRoot, ".git"); got != want {
+		t.Errorf("FindGitCommonDir(main) = %q, want %q", got, want)
+	}
+
+	got, err = FindGitCommonDir(linkedRoot)
+	if err != nil {
+		t.Fatalf("FindGitCommonDir(linked): %v", err)
+	}
+	if want := filepath.Join(mainRoot, ".git"); got != want {
+		t.Errorf("FindGitCommonDir(linked) = %q, want %q", got, want)
+	}
+}
+
+func TestMainWorktreePath(t *testing.T) {
+	mainRoot, linkedRoot := setupLinkedWorktree(t)
+
+	got, err := MainWorktreePath(linkedRoot)
+	if err != nil {
+		t.Fatalf("MainWorktreePath(linked): %v", err)
+	}
+	if got != mainRoot {
+		t.Errorf("MainWorktreePath(linked) = %q, want %q", got, mainRoot)
+	}
+
+	got, err = MainWorktreePath(mainRoot)
+	if err != nil {
+		t.Fatalf("MainWorktreePath(main): %v", err)
+	}
+	if got != mainRoot {
+		t.Errorf("MainWorktreePath(main) = %q, want %q", got, mainRoot)
+	}
+}